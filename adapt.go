@@ -0,0 +1,97 @@
+package cosmo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// SetPointerAdaptation sets whether the container adapts between T and *T. When
+// enabled, a type without a provider is resolved from the provider of its pointer,
+// dereferencing the instance, or from the provider of its element, for pointers,
+// which point to a copy of the instance:
+//
+//	c.SetPointerAdaptation(true)
+//	c.AddSingleton(func() *Config { return &Config{} })
+//	c.Invoke(func(cfg Config) { ... })
+//
+// Since every resolution of *T from a T provider copies the instance, changes made
+// through the pointer are not shared, even for singletons.
+func (c *Container) SetPointerAdaptation(enabled bool) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	c.adaptPointers = enabled
+	return nil
+}
+
+// Alias resolves from with the provider of to, converting the instance. It allows
+// resolving defined types, like type UserID string, or interfaces implemented by
+// a registered type, without another constructor:
+//
+//	c.Alias(reflect.TypeFor[Reader](), reflect.TypeFor[*File]())
+//
+// Providers registered for from take precedence over the alias.
+func (c *Container) Alias(from, to reflect.Type) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	if !to.ConvertibleTo(from) {
+		return fmt.Errorf("can't alias %v to %v, it's not convertible", from, to)
+	}
+	for t, ok := to, true; ok; t, ok = c.aliases[t] {
+		if t == from {
+			return fmt.Errorf("alias of %v to %v is circular", from, to)
+		}
+	}
+
+	c.aliases[from] = to
+	return c.EvictInstance(from)
+}
+
+// adaptation returns the type whose provider resolves t, when t has no provider of
+// its own but is an alias, or adapted from or to a pointer.
+func (c *Container) adaptation(t reflect.Type) (reflect.Type, bool) {
+	if to, ok := c.aliases[t]; ok && c.HasProvider(to) {
+		return to, true
+	}
+	if !c.adaptPointers {
+		return nil, false
+	}
+	if t.Kind() == reflect.Pointer {
+		if _, ok := c.provider(t.Elem()); ok {
+			return t.Elem(), true
+		}
+	}
+	if _, ok := c.provider(reflect.PointerTo(t)); ok {
+		return reflect.PointerTo(t), true
+	}
+	return nil, false
+}
+
+// resolveAdapted resolves t from the provider of its adaptation, if it has one.
+func (c *Container) resolveAdapted(ctx context.Context, t reflect.Type) (reflect.Value, bool, error) {
+	from, ok := c.adaptation(t)
+	if !ok {
+		return reflect.Value{}, false, nil
+	}
+
+	v, err := c.resolve(ctx, from)
+	if err != nil {
+		return reflect.Value{}, true, err
+	}
+
+	switch {
+	case from == reflect.PointerTo(t):
+		if v.IsNil() {
+			return reflect.Value{}, true, fmt.Errorf("can't adapt %v to %v, the instance is nil", from, t)
+		}
+		return v.Elem(), true, nil
+	case t.Kind() == reflect.Pointer && from == t.Elem():
+		ptr := reflect.New(from)
+		ptr.Elem().Set(v)
+		return ptr, true, nil
+	default:
+		return v.Convert(t), true, nil
+	}
+}
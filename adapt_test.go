@@ -0,0 +1,82 @@
+package cosmo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type DatabaseURL string
+
+func TestPointerAdaptation(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() *Config { return &Config{URL: DBURL} })
+	c.AddSingleton(func() SQLDBService { return SQLDBService{Config: Config{URL: DBURL}} })
+
+	if err := c.Invoke(func(Config) {}); !errors.Is(err, ErrNoProvider) {
+		t.Error("pointer was adapted without enabling it")
+	}
+
+	c.SetPointerAdaptation(true)
+	err := c.Invoke(func(cfg Config, db *SQLDBService) {
+		if cfg.URL != DBURL || db.Config.URL != DBURL {
+			t.Error("wrong adapted instances")
+		}
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !c.HasProvider(reflect.TypeFor[Config]()) {
+		t.Error("adapted type was not reported by HasProvider")
+	}
+
+	c.AddSingleton(func() Config { return Config{URL: "postgres://value"} })
+	c.Invoke(func(cfg Config) {
+		if cfg.URL != "postgres://value" {
+			t.Error("provider of the type was not used over the adaptation")
+		}
+	})
+}
+
+func TestPointerAdaptationNil(t *testing.T) {
+	c := New()
+	c.SetPointerAdaptation(true)
+	c.Add(func() *Config { return nil })
+
+	if err := c.Invoke(func(Config) {}); err == nil {
+		t.Error("nil pointer was adapted")
+	}
+}
+
+func TestAlias(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() string { return DBURL })
+	c.Add(func(cfg Config) *SQLDBService { return &SQLDBService{Config: cfg} })
+	c.AddSingleton(func() Config { return Config{URL: DBURL} })
+
+	if err := c.Alias(reflect.TypeFor[DatabaseURL](), reflect.TypeFor[string]()); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := c.Alias(reflect.TypeFor[DBService](), reflect.TypeFor[*SQLDBService]()); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err := c.Invoke(func(url DatabaseURL, db DBService) {
+		if string(url) != DBURL {
+			t.Errorf("wrong alias value %q", url)
+		}
+		if _, ok := db.(*SQLDBService); !ok {
+			t.Errorf("wrong alias implementation %T", db)
+		}
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := c.Alias(reflect.TypeFor[DatabaseURL](), reflect.TypeFor[Config]()); err == nil {
+		t.Error("alias to a type that's not convertible did not return error")
+	}
+	if err := c.Alias(reflect.TypeFor[string](), reflect.TypeFor[DatabaseURL]()); err == nil {
+		t.Error("circular alias did not return error")
+	}
+}
@@ -0,0 +1,147 @@
+package cosmo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Arguments holds values supplied by the caller to InvokeWith and ResolveWith.
+type Arguments struct {
+	values []reflect.Value
+}
+
+// Args returns the values as Arguments. Each value is passed to the first parameter
+// of its type, or else to the first one it's assignable to, which isn't already
+// supplied. A nil value is passed to the first parameter accepting nil.
+func Args(values ...any) Arguments {
+	args := Arguments{values: make([]reflect.Value, len(values))}
+	for i, v := range values {
+		args.values[i] = reflect.ValueOf(v)
+	}
+	return args
+}
+
+// InvokeWith runs the function like Container.Invoke, passing the supplied arguments
+// to the parameters of their types and resolving the others from the container.
+//
+//	err := c.InvokeWith(func(db DBService, orderID string) error {
+//		return db.Process(orderID)
+//	}, cosmo.Args(orderID))
+func (c *Container) InvokeWith(fn any, args Arguments) error {
+	_, err := c.invoke(c.Context(), fn, args)
+	return err
+}
+
+// ResolveWith builds a T with its provider constructor, passing it the supplied
+// arguments, like InvokeWith, which allows mixing dependencies of the container with
+// data known at runtime. The instance isn't cached, even for singleton providers.
+//
+//	c.Add(func(db DBService, orderID string) *OrderProcessor { ... })
+//	processor, err := cosmo.ResolveWith[*OrderProcessor](c, orderID)
+func ResolveWith[T any](c *Container, args ...any) (T, error) {
+	var result T
+
+	v, err := c.resolveWith(c.Context(), reflect.TypeFor[T](), Args(args...))
+	if err != nil {
+		return result, err
+	}
+
+	result, _ = v.Interface().(T)
+	return result, nil
+}
+
+// resolveWith calls the provider of t with the supplied arguments.
+func (c *Container) resolveWith(ctx context.Context, t reflect.Type, args Arguments) (reflect.Value, error) {
+	provider, ok := c.provider(t)
+	if !ok {
+		if c.parent != nil {
+			return c.parent.resolveWith(ctx, t, args)
+		}
+		return reflect.Value{}, &NoProviderError{Type: t, Profiles: c.inactiveProfiles(t)}
+	}
+
+	provider.Scope = ScopeTransient
+	provider.args = args
+	return c.call(ctx, provider)
+}
+
+// resolveArgsWith resolves the arguments of the plan, other than the supplied ones.
+// It returns an error if a supplied argument matches no parameter.
+func (c *Container) resolveArgsWith(ctx context.Context, p *plan, supplied Arguments) ([]reflect.Value, error) {
+	if len(supplied.values) == 0 {
+		return c.resolveArgs(ctx, p)
+	}
+
+	args := make([]reflect.Value, len(p.args))
+	used := make([]bool, len(supplied.values))
+
+	// Values of the exact type of a parameter are matched first, so they aren't taken
+	// by a previous parameter they're only assignable to.
+	for _, exact := range []bool{true, false} {
+		for i, t := range p.args {
+			if args[i].IsValid() {
+				continue
+			}
+			if j := supplied.match(t, used, exact); j >= 0 {
+				args[i] = supplied.values[j]
+				if !args[i].IsValid() {
+					args[i] = reflect.Zero(t)
+				}
+				used[j] = true
+			}
+		}
+	}
+
+	for j, v := range supplied.values {
+		if !used[j] {
+			return nil, fmt.Errorf("supplied argument %v doesn't match any parameter", describeArg(v))
+		}
+	}
+
+	for i := range p.args {
+		if args[i].IsValid() {
+			continue
+		}
+		val, err := c.resolveArg(ctx, p, i)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = val
+	}
+
+	return args, nil
+}
+
+// match returns the index of the first unused value of type t, or assignable to it
+// when not exact, or -1 if there is none.
+func (a Arguments) match(t reflect.Type, used []bool, exact bool) int {
+	for j, v := range a.values {
+		switch {
+		case used[j]:
+		case exact && v.IsValid() && v.Type() == t:
+			return j
+		case !exact && !v.IsValid() && canBeNil(t):
+			return j
+		case !exact && v.IsValid() && v.Type().AssignableTo(t):
+			return j
+		}
+	}
+	return -1
+}
+
+// canBeNil reports whether nil can be assigned to the type t.
+func canBeNil(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func describeArg(v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+	return v.Type().String()
+}
@@ -0,0 +1,65 @@
+package cosmo
+
+import "testing"
+
+type OrderProcessor struct {
+	DB      DBService
+	OrderID string
+}
+
+func TestInvokeWith(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() Config { return Config{URL: DBURL} })
+	c.Add(func(cfg Config) DBService { return &SQLDBService{Config: cfg} })
+
+	err := c.InvokeWith(func(db DBService, orderID string, cfg *Config) {
+		if db == nil || orderID != "order-1" || cfg != nil {
+			t.Error("wrong arguments")
+		}
+	}, Args("order-1", (*Config)(nil)))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	c.InvokeWith(func(db DBService) {
+		if db != nil {
+			t.Error("nil argument was not supplied")
+		}
+	}, Args(nil))
+
+	if err := c.InvokeWith(func(db DBService) {}, Args(42)); err == nil {
+		t.Error("unmatched argument did not return error")
+	}
+}
+
+func TestResolveWith(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() Config { return Config{URL: DBURL} })
+	c.Add(func(cfg Config) DBService { return &SQLDBService{Config: cfg} })
+	c.AddSingleton(func(db DBService, orderID string) *OrderProcessor {
+		return &OrderProcessor{DB: db, OrderID: orderID}
+	})
+
+	first, err := ResolveWith[*OrderProcessor](c, "order-1")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	second, err := ResolveWith[*OrderProcessor](c, "order-2")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if first.OrderID != "order-1" || second.OrderID != "order-2" || first.DB == nil {
+		t.Error("wrong supplied arguments")
+	}
+
+	db := &SQLDBService{}
+	third, _ := ResolveWith[*OrderProcessor](c, "order-3", DBService(db))
+	if third.DB != db {
+		t.Error("supplied argument was not preferred over the container")
+	}
+
+	if _, err := ResolveWith[Validator](c); err == nil {
+		t.Error("resolving an unknown type did not return error")
+	}
+}
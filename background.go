@@ -0,0 +1,106 @@
+package cosmo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// backgroundInit is a singleton built by StartBackground.
+type backgroundInit struct {
+	typ      reflect.Type
+	priority int
+}
+
+// InitInBackground marks the singleton of typ, given as accepted by TypeOf, to be
+// built by StartBackground after startup instead of on its first use. Singletons
+// with a higher priority are built first. A singleton resolved before its turn is
+// built on demand, as usual. A nil type returns an error.
+//
+//	c.InitInBackground((*SearchIndex)(nil), 10)
+//	c.InitInBackground((*ReportCache)(nil), 1)
+func (c *Container) InitInBackground(typ any, priority int) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	t := TypeOf(typ)
+	if t == nil {
+		return errors.New("can't initialize a nil type in background")
+	}
+	c.background = append(c.background, backgroundInit{typ: t, priority: priority})
+	return nil
+}
+
+// BackgroundPolicy configures how StartBackground builds the singletons.
+type BackgroundPolicy struct {
+	// Pause is how long to wait between two singletons, so the initialization
+	// yields to the traffic served by the application.
+	Pause time.Duration
+}
+
+// Background tracks the singletons being built by StartBackground.
+type Background struct {
+	done chan struct{}
+	err  error
+}
+
+// StartBackground builds the singletons marked with InitInBackground in a goroutine,
+// highest priority first, and returns immediately. It stops when ctx is done.
+//
+//	err := c.Warmup(ctx)
+//	bg := c.StartBackground(ctx, cosmo.BackgroundPolicy{Pause: 50 * time.Millisecond})
+//	serve()
+func (c *Container) StartBackground(ctx context.Context, policy BackgroundPolicy) *Background {
+	pending := append([]backgroundInit(nil), c.background...)
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].priority > pending[j].priority
+	})
+
+	b := &Background{done: make(chan struct{})}
+	go func() {
+		defer close(b.done)
+
+		var errs []error
+		for i, init := range pending {
+			if i > 0 && policy.Pause > 0 {
+				select {
+				case <-time.After(policy.Pause):
+				case <-ctx.Done():
+					b.err = errors.Join(append(errs, ctx.Err())...)
+					return
+				}
+			}
+			if ctx.Err() != nil {
+				b.err = errors.Join(append(errs, ctx.Err())...)
+				return
+			}
+
+			if _, ok := c.instances().get(init.typ); ok {
+				continue
+			}
+			if _, err := c.resolve(ctx, init.typ); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		b.err = errors.Join(errs...)
+	}()
+
+	return b
+}
+
+// Done returns a channel that's closed once every singleton was built or ctx is done.
+func (b *Background) Done() <-chan struct{} {
+	return b.done
+}
+
+// Err returns the errors of the constructors that failed, once Done is closed.
+func (b *Background) Err() error {
+	select {
+	case <-b.done:
+		return b.err
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,143 @@
+package cosmo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStartBackground(t *testing.T) {
+	c := New()
+	var built []string
+
+	c.AddSingleton(func() Config {
+		built = append(built, "config")
+		return Config{}
+	})
+	c.AddSingleton(func() *Session {
+		built = append(built, "session")
+		return &Session{}
+	})
+	c.AddSingleton(func() (*Server, error) { return nil, errors.New("failed") })
+
+	c.InitInBackground(Config{}, 1)
+	c.InitInBackground((*Session)(nil), 10)
+	c.InitInBackground((*Server)(nil), 0)
+
+	bg := c.StartBackground(context.Background(), BackgroundPolicy{Pause: time.Millisecond})
+	select {
+	case <-bg.Done():
+	case <-time.After(time.Second):
+		t.Fatal("background initialization did not finish")
+	}
+
+	if !reflect.DeepEqual(built, []string{"session", "config"}) {
+		t.Errorf("singletons were built in the wrong order: %v", built)
+	}
+	if bg.Err() == nil {
+		t.Error("constructor error was not reported")
+	}
+}
+
+func TestStartBackgroundCanceled(t *testing.T) {
+	c := New()
+	built := 0
+	c.AddSingleton(func() Config { built++; return Config{} })
+	c.InitInBackground(Config{}, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	bg := c.StartBackground(ctx, BackgroundPolicy{})
+	<-bg.Done()
+	if !errors.Is(bg.Err(), context.Canceled) || built != 0 {
+		t.Error("background initialization ignored the canceled context")
+	}
+}
+
+func TestStartBackgroundConcurrentResolution(t *testing.T) {
+	c := New()
+	var built, disposed atomic.Int32
+	c.AddSingleton(func() (*ReportService, func()) {
+		built.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return &ReportService{}, func() { disposed.Add(1) }
+	})
+	c.InitInBackground((*ReportService)(nil), 0)
+
+	bg := c.StartBackground(context.Background(), BackgroundPolicy{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.Invoke(func(*ReportService) {}); err != nil {
+				t.Error(err.Error())
+			}
+		}()
+	}
+	wg.Wait()
+	<-bg.Done()
+
+	if built.Load() != 1 {
+		t.Errorf("singleton was built %d times", built.Load())
+	}
+	c.Close()
+	if disposed.Load() != 1 {
+		t.Errorf("singleton was disposed %d times", disposed.Load())
+	}
+}
+
+func TestNamedAndGroupConcurrentResolution(t *testing.T) {
+	c := New()
+	var named, member atomic.Int32
+	c.AddNamedWithScope(ScopeSingleton, "reports", func() *ReportService {
+		named.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return &ReportService{}
+	})
+	c.AddToGroupWithScope(ScopeSingleton, "reports", func() *ReportService {
+		member.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return &ReportService{}
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var deps struct {
+				Named   *ReportService   `cosmo:"name=reports"`
+				Members []*ReportService `cosmo:"group=reports"`
+			}
+			if err := c.Bind(&deps); err != nil {
+				t.Error(err.Error())
+			}
+		}()
+	}
+	wg.Wait()
+
+	if named.Load() != 1 || member.Load() != 1 {
+		t.Errorf("named singleton was built %d times, group member %d times", named.Load(), member.Load())
+	}
+}
+
+func TestInitInBackgroundNilType(t *testing.T) {
+	c := New()
+	for _, typ := range []any{nil, reflect.Type(nil)} {
+		if err := c.InitInBackground(typ, 1); err == nil {
+			t.Errorf("InitInBackground(%v) did not return error", typ)
+		}
+	}
+
+	bg := c.StartBackground(context.Background(), BackgroundPolicy{})
+	<-bg.Done()
+	if err := bg.Err(); err != nil {
+		t.Error(err.Error())
+	}
+}
@@ -0,0 +1,76 @@
+package cosmo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Builder registers providers in a new container, collecting the registration
+// errors so they are checked once, when the container is built.
+//
+//	c, err := cosmo.NewBuilder().
+//		ProvideSingleton(NewConfig).
+//		ProvideSingleton(NewDB).
+//		Provide(NewUserService).
+//		Build()
+type Builder struct {
+	c    *Container
+	errs []error
+}
+
+// NewBuilder returns a Builder of a new container.
+func NewBuilder() *Builder {
+	return &Builder{c: New()}
+}
+
+// Provide adds the constructor with ScopeTransient.
+func (b *Builder) Provide(constructor any) *Builder {
+	return b.ProvideWithScope(ScopeTransient, constructor)
+}
+
+// ProvideSingleton adds the constructor with ScopeSingleton.
+func (b *Builder) ProvideSingleton(constructor any) *Builder {
+	return b.ProvideWithScope(ScopeSingleton, constructor)
+}
+
+// ProvideWithScope adds the constructor with the specified scope.
+func (b *Builder) ProvideWithScope(scope Scope, constructor any) *Builder {
+	return b.check("provide", constructor, b.c.AddWithScope(scope, constructor))
+}
+
+// ProvideNamed adds the constructor under name, using the specified scope.
+func (b *Builder) ProvideNamed(scope Scope, name string, constructor any) *Builder {
+	return b.check(fmt.Sprintf("provide %q", name), constructor, b.c.AddNamedWithScope(scope, name, constructor))
+}
+
+// Configure adds the constructor as a configuration under key.
+func (b *Builder) Configure(key string, constructor any) *Builder {
+	return b.check(fmt.Sprintf("configure %q", key), constructor, b.c.Configure(key, constructor))
+}
+
+// Decorate registers the decorator.
+func (b *Builder) Decorate(fn any) *Builder {
+	return b.check("decorate", fn, b.c.Decorate(fn))
+}
+
+// Build returns the container, or the errors of every failed registration.
+func (b *Builder) Build() (*Container, error) {
+	if err := errors.Join(b.errs...); err != nil {
+		return nil, err
+	}
+	return b.c, nil
+}
+
+// check records err, describing the registration of fn that failed.
+func (b *Builder) check(op string, fn any, err error) *Builder {
+	if err == nil {
+		return b
+	}
+	name := fmt.Sprintf("%T", fn)
+	if v := reflect.ValueOf(fn); v.Kind() == reflect.Func {
+		name = funcName(v)
+	}
+	b.errs = append(b.errs, fmt.Errorf("%s %s: %w", op, name, err))
+	return b
+}
@@ -0,0 +1,40 @@
+package cosmo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	c, err := NewBuilder().
+		ProvideSingleton(func() Config { return Config{URL: DBURL} }).
+		Provide(func(cfg Config) DBService { return &SQLDBService{Config: cfg} }).
+		Configure("config", func() Config { return Config{URL: DBURL} }).
+		Build()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := c.Invoke(func(db DBService) {}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	c, err := NewBuilder().
+		Provide("not a constructor").
+		ProvideSingleton(func() Config { return Config{URL: DBURL} }).
+		Decorate(func() {}).
+		Build()
+
+	if c != nil {
+		t.Error("builder with errors returned a container")
+	}
+	if !errors.Is(err, ErrInvalidConstructor) {
+		t.Errorf("wrong error %v", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "provide string") || !strings.Contains(msg, "decorate") {
+		t.Errorf("registration errors were not aggregated: %s", msg)
+	}
+}
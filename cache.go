@@ -0,0 +1,320 @@
+package cosmo
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// cache holds the instances built by the container, and the cleanup functions that
+// dispose them. It's safe for concurrent use.
+type cache struct {
+	mu       sync.RWMutex
+	types    map[reflect.Type]reflect.Value
+	named    map[string]reflect.Value
+	groups   map[string][]reflect.Value
+	cleanups []cleanup
+	expiring map[reflect.Type]*expiringInstance
+	inflight map[any]*flight
+}
+
+// flight is a singleton being built. Done is closed once it's built, or failed
+// with err.
+type flight struct {
+	done chan struct{}
+	err  error
+}
+
+// cleanup disposes an instance. It keeps the spec of the provider that built the
+// instance, so the instances of a group or of a type can be disposed separately.
+type cleanup struct {
+	spec Spec
+	fn   func() error
+}
+
+func newCache() *cache {
+	return &cache{
+		types:    make(map[reflect.Type]reflect.Value),
+		named:    make(map[string]reflect.Value),
+		groups:   make(map[string][]reflect.Value),
+		expiring: make(map[reflect.Type]*expiringInstance),
+		inflight: make(map[any]*flight),
+	}
+}
+
+func (c *cache) get(t reflect.Type) (reflect.Value, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok := c.types[t]
+	return v, ok
+}
+
+func (c *cache) set(t reflect.Type, v reflect.Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.types[t] = v
+}
+
+// namedSlot and groupSlot identify the instance of a named provider and of a group
+// member in join and store, where a reflect.Type identifies the instance of a type.
+type namedSlot string
+
+type groupSlot struct {
+	name  string
+	index int
+}
+
+// join returns the instance cached in slot, or the flight building it under key.
+// When there is none, it starts a flight, and leader is true: the caller must build
+// the instance and call land.
+func (c *cache) join(slot, key any) (v reflect.Value, cached bool, f *flight, leader bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v, ok := c.lookup(slot); ok {
+		return v, true, nil, false
+	}
+	if f, ok := c.inflight[key]; ok {
+		return reflect.Value{}, false, f, false
+	}
+	f = &flight{done: make(chan struct{})}
+	c.inflight[key] = f
+	return reflect.Value{}, false, f, true
+}
+
+// lookup returns the instance cached in slot. The cache must be locked.
+func (c *cache) lookup(slot any) (reflect.Value, bool) {
+	switch s := slot.(type) {
+	case namedSlot:
+		v, ok := c.named[string(s)]
+		return v, ok
+	case groupSlot:
+		members := c.groups[s.name]
+		if s.index >= len(members) || !members[s.index].IsValid() {
+			return reflect.Value{}, false
+		}
+		return members[s.index], true
+	default:
+		v, ok := c.types[slot.(reflect.Type)]
+		return v, ok
+	}
+}
+
+// store caches the instance in slot.
+func (c *cache) store(slot any, v reflect.Value) {
+	switch s := slot.(type) {
+	case namedSlot:
+		c.setNamed(string(s), v)
+	case groupSlot:
+		c.setGroup(s.name, s.index, v)
+	default:
+		c.set(slot.(reflect.Type), v)
+	}
+}
+
+// land ends the flight started by join.
+func (c *cache) land(key any, f *flight, err error) {
+	c.mu.Lock()
+	delete(c.inflight, key)
+	c.mu.Unlock()
+	f.err = err
+	close(f.done)
+}
+
+// snapshot returns a copy of the instances cached by type.
+func (c *cache) snapshot() map[reflect.Type]reflect.Value {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	types := make(map[reflect.Type]reflect.Value, len(c.types))
+	for t, v := range c.types {
+		types[t] = v
+	}
+	return types
+}
+
+func (c *cache) delete(t reflect.Type) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.types, t)
+}
+
+func (c *cache) getNamed(name string) (reflect.Value, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok := c.named[name]
+	return v, ok
+}
+
+func (c *cache) setNamed(name string, v reflect.Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.named[name] = v
+}
+
+func (c *cache) deleteNamed(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.named, name)
+}
+
+// getGroup returns the instance of the member i of the named group.
+func (c *cache) getGroup(name string, i int) (reflect.Value, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	members := c.groups[name]
+	if i >= len(members) || !members[i].IsValid() {
+		return reflect.Value{}, false
+	}
+	return members[i], true
+}
+
+// setGroup stores the instance of the member i of the named group.
+func (c *cache) setGroup(name string, i int, v reflect.Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	members := c.groups[name]
+	if i >= len(members) {
+		members = append(members, make([]reflect.Value, i+1-len(members))...)
+	}
+	members[i] = v
+	c.groups[name] = members
+}
+
+func (c *cache) addCleanup(spec Spec, fn func() error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cleanups = append(c.cleanups, cleanup{spec: spec, fn: fn})
+}
+
+// reset discards every instance, keeping the memory of the maps to be reused.
+func (c *cache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.types)
+	clear(c.named)
+	clear(c.groups)
+	clear(c.expiring)
+	clear(c.inflight)
+}
+
+// dispose runs the cleanup functions in the reverse order they were added.
+func (c *cache) dispose() error {
+	return runCleanups(c.takeCleanups())
+}
+
+// takeCleanups removes and returns the cleanup functions.
+func (c *cache) takeCleanups() []cleanup {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cleanups := c.cleanups
+	c.cleanups = nil
+	return cleanups
+}
+
+// disposeGroup runs the cleanup functions of the members of the named group, in the
+// reverse order they were added, and discards the cached members.
+func (c *cache) disposeGroup(name string) error {
+	c.mu.Lock()
+	var cleanups, kept []cleanup
+	for _, cl := range c.cleanups {
+		if cl.spec.Group == name {
+			cleanups = append(cleanups, cl)
+		} else {
+			kept = append(kept, cl)
+		}
+	}
+	c.cleanups = kept
+	delete(c.groups, name)
+	c.mu.Unlock()
+
+	return runCleanups(cleanups)
+}
+
+// evict discards the singletons and expiring instances of the types, returning the
+// cleanup functions of the discarded instances. Named providers and group members
+// are not evicted.
+func (c *cache) evict(types map[reflect.Type]bool) []cleanup {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for t := range types {
+		delete(c.types, t)
+		delete(c.expiring, t)
+	}
+
+	var evicted, kept []cleanup
+	for _, cl := range c.cleanups {
+		spec := cl.spec
+		if types[spec.Type] && spec.Scope != ScopeTransient && spec.Name == "" && spec.Group == "" {
+			evicted = append(evicted, cl)
+		} else {
+			kept = append(kept, cl)
+		}
+	}
+	c.cleanups = kept
+
+	return evicted
+}
+
+// expiringInstance returns the instance of the expiring provider of t, creating it
+// if needed.
+func (c *cache) expiringInstance(t reflect.Type) *expiringInstance {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	inst, ok := c.expiring[t]
+	if !ok {
+		inst = &expiringInstance{}
+		c.expiring[t] = inst
+	}
+	return inst
+}
+
+// takeExpired removes and returns the cleanup functions of the expiring instance
+// of t.
+func (c *cache) takeExpired(t reflect.Type) []cleanup {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expired, kept []cleanup
+	for _, cl := range c.cleanups {
+		spec := cl.spec
+		if spec.Type == t && spec.Scope == ScopeExpiring && spec.Name == "" && spec.Group == "" {
+			expired = append(expired, cl)
+		} else {
+			kept = append(kept, cl)
+		}
+	}
+	c.cleanups = kept
+
+	return expired
+}
+
+// evictNamed discards the singleton of the named provider, returning the cleanup
+// function of the discarded instance.
+func (c *cache) evictNamed(name string) []cleanup {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.named, name)
+
+	var evicted, kept []cleanup
+	for _, cl := range c.cleanups {
+		if cl.spec.Name == name {
+			evicted = append(evicted, cl)
+		} else {
+			kept = append(kept, cl)
+		}
+	}
+	c.cleanups = kept
+
+	return evicted
+}
+
+func runCleanups(cleanups []cleanup) error {
+	var errs []error
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		if err := cleanups[i].fn(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,70 @@
+package cosmo
+
+import (
+	"context"
+	"reflect"
+)
+
+type chainKey struct{}
+
+// chain is a node of the resolution chain of a context, linked to the chain of the
+// provider that depends on it, so appending a provider doesn't copy the chain.
+type chain struct {
+	parent *chain
+	typ    reflect.Type
+	key    providerKey
+	depth  int
+}
+
+// providerKey identifies a provider, so a named provider that depends on the type
+// it builds isn't taken as a cycle.
+type providerKey struct {
+	typ   reflect.Type
+	name  string
+	group string
+	fn    uintptr
+}
+
+func keyOf(provider Spec) providerKey {
+	return providerKey{
+		typ:   provider.Type,
+		name:  provider.Name,
+		group: provider.Group,
+		fn:    provider.Value.Pointer(),
+	}
+}
+
+// Chain returns the types being resolved in ctx, the outermost first. It's useful
+// to know why a constructor was called, inside constructors, tracers and middlewares.
+func Chain(ctx context.Context) []reflect.Type {
+	ch, _ := ctx.Value(chainKey{}).(*chain)
+	if ch == nil {
+		return nil
+	}
+
+	types := make([]reflect.Type, ch.depth)
+	for node := ch; node != nil; node = node.parent {
+		types[node.depth-1] = node.typ
+	}
+	return types
+}
+
+// withChain returns a context where the provider is appended to the resolution
+// chain. It returns a *CycleError if the provider is already in the chain.
+func withChain(ctx context.Context, provider Spec) (context.Context, error) {
+	parent, _ := ctx.Value(chainKey{}).(*chain)
+
+	key := keyOf(provider)
+	for node := parent; node != nil; node = node.parent {
+		if node.key == key {
+			path := Chain(ctx)[node.depth-1:]
+			return ctx, &CycleError{Path: append(path, provider.Type)}
+		}
+	}
+
+	ch := &chain{parent: parent, typ: provider.Type, key: key, depth: 1}
+	if parent != nil {
+		ch.depth = parent.depth + 1
+	}
+	return context.WithValue(ctx, chainKey{}, ch), nil
+}
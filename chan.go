@@ -0,0 +1,24 @@
+package cosmo
+
+// Chan registers a channel of T with the given buffer size, shared by the providers
+// that depend on chan T, <-chan T or chan<- T, so producers and consumers are wired
+// without globals. The channel is created once and closed when the container is
+// closed, after the cleanups of the providers built with it.
+//
+//	cosmo.Chan[Event](c, 100)
+//	c.AddSingleton(func(events chan<- Event) *Publisher { ... })
+//	c.AddSingleton(func(events <-chan Event) *Consumer { ... })
+func Chan[T any](c *Container, buffer int) error {
+	err := c.AddSingleton(func() (chan T, func()) {
+		ch := make(chan T, buffer)
+		return ch, func() { close(ch) }
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := c.AddSingleton(func(ch chan T) <-chan T { return ch }); err != nil {
+		return err
+	}
+	return c.AddSingleton(func(ch chan T) chan<- T { return ch })
+}
@@ -0,0 +1,41 @@
+package cosmo
+
+import "testing"
+
+type Event struct {
+	Name string
+}
+
+type Publisher struct {
+	events chan<- Event
+}
+
+type Consumer struct {
+	events <-chan Event
+}
+
+func TestChan(t *testing.T) {
+	c := New()
+	if err := Chan[Event](c, 1); err != nil {
+		t.Fatal(err.Error())
+	}
+	c.AddSingleton(func(events chan<- Event) *Publisher { return &Publisher{events: events} })
+	c.AddSingleton(func(events <-chan Event) *Consumer { return &Consumer{events: events} })
+
+	err := c.Invoke(func(p *Publisher, consumer *Consumer) {
+		p.events <- Event{Name: "created"}
+		if e := <-consumer.events; e.Name != "created" {
+			t.Errorf("consumer received %+v", e)
+		}
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var events <-chan Event
+	c.Invoke(func(ch <-chan Event) { events = ch })
+	c.Close()
+	if _, ok := <-events; ok {
+		t.Error("channel was not closed with the container")
+	}
+}
@@ -0,0 +1,125 @@
+package cosmo
+
+import (
+	"maps"
+	"sync"
+)
+
+// Child returns a new container that resolves its own providers and falls back to
+// c for the types it doesn't provide. Singletons of the child are built and cached
+// by the child, and disposed when the child is closed, while the singletons of c
+// are shared. Dependencies of providers of c are always resolved by c, so they
+// can't depend on types registered only in the child.
+//
+// Children are useful for request or job scoped services:
+//
+//	req := c.Child()
+//	defer req.Close()
+//	req.AddSingleton(func() *User { return user })
+//	err := req.Invoke(func(svc *OrderService) error { ... })
+func (c *Container) Child() *Container {
+	child := New()
+	child.parent = c
+	child.metrics = c.metrics
+	child.tracer = c.tracer
+	child.panicPolicy = c.panicPolicy
+	child.profiles = c.profiles
+	child.adaptPointers = c.adaptPointers
+	child.redactors = maps.Clone(c.redactors)
+	for t, fields := range c.secretFields {
+		child.secretFields[t] = maps.Clone(fields)
+	}
+	return child
+}
+
+// ChildPool reuses the children of a container, so servers creating a child per
+// request don't allocate the registrations and the cache of a new container each
+// time. Children are returned to the pool with Put once the request is done:
+//
+//	pool := c.ChildPool()
+//
+//	req := pool.Get()
+//	defer pool.Put(req)
+//	req.AddSingleton(func() *User { return user })
+//
+// A child must not be used, or referenced by the instances it built, after Put.
+type ChildPool struct {
+	parent *Container
+	pool   sync.Pool
+}
+
+// ChildPool returns a pool of children of c.
+func (c *Container) ChildPool() *ChildPool {
+	return &ChildPool{parent: c}
+}
+
+// Get returns a child of the container, like Container.Child, reusing one returned
+// to the pool if there is any.
+func (p *ChildPool) Get() *Container {
+	if child, ok := p.pool.Get().(*Container); ok {
+		return child
+	}
+	return p.parent.Child()
+}
+
+// Put closes the child, like Container.Close, and returns it to the pool. Children
+// that were cloned, or that aren't children of the container, are closed but not
+// reused, since they share their registrations.
+func (p *ChildPool) Put(child *Container) error {
+	if child.parent != p.parent || child.shared.Load() {
+		return child.Close()
+	}
+	err := child.recycle()
+	p.pool.Put(child)
+	return err
+}
+
+// recycle closes the child and makes it as returned by Child, keeping the memory of
+// its maps. The child must not be in use.
+func (c *Container) recycle() error {
+	c.mu.Lock()
+	closers := c.closers
+	c.closers = nil
+	c.mu.Unlock()
+	for i := len(closers) - 1; i >= 0; i-- {
+		closers[i]()
+	}
+
+	cache := c.instances()
+	err := cache.dispose()
+	cache.reset()
+
+	clear(c.configurations)
+	clear(c.providers)
+	clear(c.groups)
+	clear(c.named)
+	clear(c.decorators)
+	clear(c.fallbacks)
+	clear(c.conditional)
+	clear(c.redactors)
+	clear(c.secretFields)
+	clear(c.tunable)
+	clear(c.aliases)
+	clear(c.setters)
+
+	parent := c.parent
+	c.generation.Store(0)
+	c.registrations.Store(0)
+	c.rebuilding.Store(nil)
+	c.metrics = parent.metrics
+	c.tracer = parent.tracer
+	c.panicPolicy = parent.panicPolicy
+	maps.Copy(c.redactors, parent.redactors)
+	for t, fields := range parent.secretFields {
+		c.secretFields[t] = maps.Clone(fields)
+	}
+	c.middlewares = nil
+	c.resolver = c.resolveType
+	c.module = ""
+	c.degraded = nil
+	c.background = nil
+	c.sealed.Store(false)
+	c.profiles = parent.profiles
+	c.adaptPointers = parent.adaptPointers
+	return err
+}
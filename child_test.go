@@ -0,0 +1,177 @@
+package cosmo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestChild(t *testing.T) {
+	c := New()
+	built := 0
+	c.AddSingleton(func() Config {
+		built++
+		return Config{URL: "parent"}
+	})
+	c.AddNamed("replica", func() Config { return Config{URL: "replica"} })
+
+	closed := 0
+	child := c.Child()
+	child.AddSingleton(func(cfg Config) (DBService, func()) {
+		return &SQLDBService{Config: cfg}, func() { closed++ }
+	})
+
+	var first DBService
+	err := child.Invoke(func(db DBService, cfg Config) {
+		first = db
+		if cfg.URL != "parent" {
+			t.Error("child did not resolve the parent provider")
+		}
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	child.Invoke(func(db DBService) {
+		if db != first {
+			t.Error("child singleton was built twice")
+		}
+	})
+	c.Invoke(func(cfg Config) {})
+	if built != 1 {
+		t.Error("parent singleton was not shared with the child")
+	}
+
+	var bnd struct {
+		Replica Config `cosmo:"name=replica"`
+	}
+	if err := child.Bind(&bnd); err != nil || bnd.Replica.URL != "replica" {
+		t.Errorf("child did not resolve the parent named provider: %v", err)
+	}
+
+	if err := c.Invoke(func(db DBService) {}); !errors.Is(err, ErrNoProvider) {
+		t.Error("parent resolved a provider of the child")
+	}
+
+	if err := child.Close(); err != nil {
+		t.Error(err.Error())
+	}
+	if closed != 1 {
+		t.Errorf("child cleanup ran %d times", closed)
+	}
+	c.Invoke(func(cfg Config) {})
+	if built != 1 {
+		t.Error("closing the child disposed the parent singletons")
+	}
+}
+
+func TestChildGet(t *testing.T) {
+	c := New()
+	c.Configure("cfg", func() Config { return Config{URL: DBURL} })
+	child := c.Child()
+
+	if cfg, ok := child.Get("cfg").(Config); !ok || cfg.URL != DBURL {
+		t.Error("child did not resolve the configuration of its parent")
+	}
+	if cfg := child.MustGet("cfg").(Config); cfg.URL != DBURL {
+		t.Error("MustGet did not resolve the configuration of the parent")
+	}
+	if child.Get("unknown") != nil {
+		t.Error("unknown key was resolved")
+	}
+}
+
+func TestChildPoolRecycle(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() Config { return Config{URL: "parent"} })
+	pool := c.ChildPool()
+
+	closed := false
+	child := pool.Get()
+	child.AddSingleton(func() (Config, func()) { return Config{URL: "child"}, func() { closed = true } })
+	child.AddNamed("replica", func() Config { return Config{URL: "replica"} })
+	child.UseResolveMiddleware(func(next ResolveFunc) ResolveFunc { return next })
+	child.Invoke(func(Config) {})
+
+	if err := child.recycle(); err != nil {
+		t.Error(err.Error())
+	}
+	if !closed {
+		t.Error("cleanup of the recycled child was not run")
+	}
+	if len(child.providers) != 0 || len(child.named) != 0 || len(child.middlewares) != 0 {
+		t.Error("registrations of the recycled child were kept")
+	}
+	if _, ok := child.instances().get(reflect.TypeFor[Config]()); ok {
+		t.Error("instance of the recycled child was kept")
+	}
+	child.Invoke(func(cfg Config) {
+		if cfg.URL != "parent" {
+			t.Errorf("recycled child resolved %q", cfg.URL)
+		}
+	})
+	if err := pool.Put(child); err != nil {
+		t.Error(err.Error())
+	}
+
+	clone := pool.Get().Clone()
+	if err := pool.Put(clone); err != nil {
+		t.Error(err.Error())
+	}
+	if !clone.shared.Load() {
+		t.Error("cloned child was recycled")
+	}
+}
+
+// TestChildPoolFields fails when a field is added to Container, as a reminder to reset
+// it in recycle.
+func TestChildPoolFields(t *testing.T) {
+	recycled := []string{
+		"configurations", "providers", "groups", "named", "decorators", "cache", "generation",
+		"middlewares", "resolver", "parent", "metrics", "tracer", "mu", "closers",
+		"panicPolicy", "rebuildMu", "rebuilding", "redactors", "secretFields",
+		"registrations", "module", "fallbacks", "degraded", "background", "conditional",
+		"profiles", "sealed", "shared", "tunable", "aliases", "adaptPointers",
+		"setters",
+	}
+	typ := reflect.TypeFor[Container]()
+	if typ.NumField() != len(recycled) {
+		t.Fatalf("Container has %d fields, recycle resets %d", typ.NumField(), len(recycled))
+	}
+	for _, name := range recycled {
+		if _, ok := typ.FieldByName(name); !ok {
+			t.Errorf("Container has no field %s", name)
+		}
+	}
+}
+
+func BenchmarkChild(b *testing.B) {
+	c := New()
+	c.AddSingleton(func() Config { return Config{URL: DBURL} })
+	fn := func(DBService) {}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		child := c.Child()
+		child.AddSingleton(func(cfg Config) DBService { return &SQLDBService{Config: cfg} })
+		if err := child.Invoke(fn); err != nil {
+			b.Fatal(err.Error())
+		}
+	}
+}
+
+func BenchmarkChildPool(b *testing.B) {
+	c := New()
+	c.AddSingleton(func() Config { return Config{URL: DBURL} })
+	pool := c.ChildPool()
+	fn := func(DBService) {}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		child := pool.Get()
+		child.AddSingleton(func(cfg Config) DBService { return &SQLDBService{Config: cfg} })
+		if err := child.Invoke(fn); err != nil {
+			b.Fatal(err.Error())
+		}
+		pool.Put(child)
+	}
+}
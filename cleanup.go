@@ -0,0 +1,39 @@
+package cosmo
+
+import (
+	"context"
+	"reflect"
+)
+
+var cleanupType = reflect.TypeFor[func()]()
+
+// Close stops the facilities attached to the container, like warm pools, runs the
+// cleanup functions returned by the constructors, in the reverse order the instances
+// were created, and discards the cached singletons so they're built again if the
+// container is used after being closed. It returns the errors of closing the group
+// members that implement io.Closer. Use Shutdown to get a report of each step.
+//
+// Only the cleanups of the instances kept by the container, built by singleton and
+// expiring providers, are run. The container doesn't keep transient instances, so
+// the cleanup functions returned by transient providers are discarded, and their
+// instances must be disposed by their consumers.
+//
+//	c.AddSingleton(func(cfg Config) (*sql.DB, func(), error) {
+//		db, err := sql.Open("sqlite", cfg.URL)
+//		if err != nil {
+//			return nil, nil, err
+//		}
+//		return db, func() { db.Close() }, nil
+//	})
+//	defer c.Close()
+func (c *Container) Close() error {
+	return c.Shutdown(context.Background()).Err()
+}
+
+// onClose adds a function called when the container is closed, before the
+// instances are disposed.
+func (c *Container) onClose(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closers = append(c.closers, fn)
+}
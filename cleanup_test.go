@@ -0,0 +1,85 @@
+package cosmo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCleanup(t *testing.T) {
+	c := New()
+	var closed []string
+
+	c.AddSingleton(func() (Config, func(), error) {
+		return Config{URL: DBURL}, func() { closed = append(closed, "Config") }, nil
+	})
+	c.AddSingleton(func(cfg Config) (DBService, error, func()) {
+		return &SQLDBService{Config: cfg}, nil, func() { closed = append(closed, "DBService") }
+	})
+
+	err := c.Invoke(func(db DBService) {})
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	if err = c.Close(); err != nil {
+		t.Error(err.Error())
+	}
+
+	if !reflect.DeepEqual(closed, []string{"DBService", "Config"}) {
+		t.Errorf("cleanups were called in the wrong order: %v", closed)
+	}
+
+	if err = c.Close(); err != nil || len(closed) != 2 {
+		t.Error("cleanups were called more than once")
+	}
+}
+
+func TestCleanupConstructorError(t *testing.T) {
+	c := New()
+	cleaned := false
+
+	c.Add(func() (Config, func(), error) {
+		return Config{}, func() { cleaned = true }, errors.New("failed")
+	})
+
+	if err := c.Invoke(func(cfg Config) {}); err == nil {
+		t.Error("constructor error was not returned")
+	}
+
+	c.Close()
+	if cleaned {
+		t.Error("cleanup of a failed constructor was called")
+	}
+}
+
+func TestInvalidResults(t *testing.T) {
+	c := New()
+	if err := c.Add(func() (Config, error, int) { return Config{}, nil, 0 }); err == nil {
+		t.Error("invalid validation for ctor results")
+	}
+	if err := c.Add(func() (Config, error, error) { return Config{}, nil, nil }); err == nil {
+		t.Error("invalid validation for ctor results")
+	}
+}
+
+func TestCleanupTransient(t *testing.T) {
+	c := New()
+	cleaned := 0
+	c.Add(func() (Config, func()) {
+		return Config{URL: DBURL}, func() { cleaned++ }
+	})
+	c.AddToGroup("handlers", func() *RouteHandler { return &RouteHandler{} })
+
+	for range 10 {
+		c.Invoke(func(Config, []*RouteHandler) {})
+	}
+	if n := len(c.instances().cleanups); n != 0 {
+		t.Errorf("%d cleanups of transient instances were recorded", n)
+	}
+
+	c.Close()
+	if cleaned != 0 {
+		t.Error("cleanup of a transient instance was run")
+	}
+}
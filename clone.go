@@ -0,0 +1,109 @@
+package cosmo
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// Clone returns an independent copy of the registrations of the container, without
+// its cached singletons. The copy is cheap: the registrations are shared until
+// either container changes them. It's meant for tests that override a few providers
+// of the production container without affecting it:
+//
+//	c := app.NewContainer()
+//	test := c.Clone()
+//	test.AddSingleton(NewFakeMailer)
+//
+// The clone isn't sealed, and its singletons are built and disposed by the clone.
+func (c *Container) Clone() *Container {
+	clone := &Container{
+		configurations: c.configurations,
+		providers:      c.providers,
+		groups:         c.groups,
+		named:          c.named,
+		decorators:     c.decorators,
+		metrics:        c.metrics,
+		tracer:         c.tracer,
+		panicPolicy:    c.panicPolicy,
+		redactors:      c.redactors,
+		secretFields:   c.secretFields,
+		middlewares:    c.middlewares,
+		parent:         c.parent,
+		fallbacks:      c.fallbacks,
+		degraded:       slices.Clone(c.degraded),
+		background:     c.background,
+		conditional:    c.conditional,
+		profiles:       c.profiles,
+		tunable:        c.tunable,
+		aliases:        c.aliases,
+		adaptPointers:  c.adaptPointers,
+		setters:        c.setters,
+	}
+	clone.registrations.Store(c.registrations.Load())
+	clone.resolver = clone.buildResolver()
+	clone.cache.Store(newCache())
+
+	c.shared.Store(true)
+	clone.shared.Store(true)
+
+	return clone
+}
+
+// CloneWithSingletons returns a clone of the container, like Clone, that reuses the
+// singletons already built by the container. The singletons are still disposed by
+// the container they were built by.
+func (c *Container) CloneWithSingletons() *Container {
+	clone := c.Clone()
+	for t, v := range c.instances().snapshot() {
+		clone.instances().set(t, v)
+	}
+	return clone
+}
+
+// own copies the registrations shared with a clone, so they can be changed.
+func (c *Container) own() {
+	if !c.shared.CompareAndSwap(true, false) {
+		return
+	}
+
+	c.configurations = maps.Clone(c.configurations)
+	c.providers = maps.Clone(c.providers)
+	c.named = maps.Clone(c.named)
+	c.fallbacks = maps.Clone(c.fallbacks)
+	c.redactors = maps.Clone(c.redactors)
+	c.tunable = maps.Clone(c.tunable)
+	c.aliases = maps.Clone(c.aliases)
+	c.middlewares = slices.Clip(c.middlewares)
+	c.background = slices.Clip(c.background)
+
+	groups := make(map[string]*group, len(c.groups))
+	for name, g := range c.groups {
+		groups[name] = &group{typ: g.typ, specs: slices.Clone(g.specs)}
+	}
+	c.groups = groups
+
+	decorators := make(map[reflect.Type][]reflect.Value, len(c.decorators))
+	for t, fns := range c.decorators {
+		decorators[t] = slices.Clip(fns)
+	}
+	c.decorators = decorators
+
+	setters := make(map[reflect.Type][]string, len(c.setters))
+	for t, names := range c.setters {
+		setters[t] = slices.Clip(names)
+	}
+	c.setters = setters
+
+	conditional := make(map[reflect.Type][]Spec, len(c.conditional))
+	for t, specs := range c.conditional {
+		conditional[t] = slices.Clip(specs)
+	}
+	c.conditional = conditional
+
+	secretFields := make(map[reflect.Type]map[string]bool, len(c.secretFields))
+	for t, fields := range c.secretFields {
+		secretFields[t] = maps.Clone(fields)
+	}
+	c.secretFields = secretFields
+}
@@ -0,0 +1,49 @@
+package cosmo
+
+import "testing"
+
+func TestClone(t *testing.T) {
+	c := New()
+	built := 0
+	c.AddSingleton(func() Config {
+		built++
+		return Config{URL: DBURL}
+	})
+	c.Add(func(cfg Config) DBService { return &SQLDBService{Config: cfg} })
+	c.AddToGroup("validators", func() Validator { return &NotEmptyValidator{} })
+	c.Invoke(func(Config) {})
+	c.Seal()
+
+	clone := c.Clone()
+	if err := clone.Add(func() DBService { return &MemoryDBService{} }); err != nil {
+		t.Fatal(err.Error())
+	}
+	clone.AddToGroup("validators", func() Validator { return &MaxLenValidator{Max: 1} })
+
+	clone.Invoke(func(db DBService, cfg Config, validators []Validator) {
+		if _, ok := db.(*MemoryDBService); !ok {
+			t.Error("clone didn't use its override")
+		}
+		if len(validators) != 2 {
+			t.Errorf("clone resolved %d validators", len(validators))
+		}
+	})
+	if built != 2 {
+		t.Error("clone reused the singletons of the container")
+	}
+
+	c.Invoke(func(db DBService, validators []Validator) {
+		if _, ok := db.(*SQLDBService); !ok {
+			t.Error("override of the clone changed the container")
+		}
+		if len(validators) != 1 {
+			t.Errorf("group of the container has %d validators", len(validators))
+		}
+	})
+
+	shared := c.CloneWithSingletons()
+	shared.Invoke(func(Config) {})
+	if built != 2 {
+		t.Error("clone with singletons built the singleton again")
+	}
+}
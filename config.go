@@ -0,0 +1,218 @@
+package cosmo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ConfigDecoders maps file extensions to the functions used by ConfigureFromFile to
+// decode configuration files. JSON is supported out of the box, other formats can
+// be added by the application, like:
+//
+//	cosmo.ConfigDecoders[".yaml"] = yaml.Unmarshal
+var ConfigDecoders = map[string]func(data []byte, v any) error{
+	".json": json.Unmarshal,
+}
+
+// ConfigureFromEnv fills the struct pointed by target with environment variables and
+// registers it with Configure under key, so it's resolvable by type and by key.
+//
+// Each field is read from the variable named by its `env` tag, or from its name in
+// upper snake case, prefixed by key and an underscore. Nested structs are filled
+// the same way, using the field variable as the prefix. Fields without a variable
+// keep the value they had in target, so target can carry the defaults.
+//
+//	type DBConfig struct {
+//		URL      string        `env:"URL,required"` // DB_URL
+//		MaxConns int                                // DB_MAX_CONNS
+//		Timeout  time.Duration                      // DB_TIMEOUT
+//	}
+//
+//	err := c.ConfigureFromEnv("DB", &DBConfig{MaxConns: 10})
+//
+// Strings, booleans, numbers, time.Duration and comma separated slices of them
+// are supported.
+func (c *Container) ConfigureFromEnv(key string, target any) error {
+	v, err := configTarget(target)
+	if err != nil {
+		return err
+	}
+
+	if err := fillFromEnv(v, key); err != nil {
+		return fmt.Errorf("configuration %q: %w", key, err)
+	}
+
+	return c.configureValue(key, v)
+}
+
+// ConfigureFromFile decodes the file at path into the struct pointed by target,
+// using the decoder registered in ConfigDecoders for the file extension, and
+// registers it with Configure under key. The file can be combined with
+// ConfigureFromEnv, using the same target, so environment variables override it.
+func (c *Container) ConfigureFromFile(key string, path string, target any) error {
+	v, err := configTarget(target)
+	if err != nil {
+		return err
+	}
+
+	decode, ok := ConfigDecoders[filepath.Ext(path)]
+	if !ok {
+		return fmt.Errorf("configuration %q: no decoder for %q files", key, filepath.Ext(path))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("configuration %q: %w", key, err)
+	}
+
+	if err := decode(data, target); err != nil {
+		return fmt.Errorf("configuration %q: decoding %s: %w", key, path, err)
+	}
+
+	return c.configureValue(key, v)
+}
+
+// configTarget validates that target is a pointer to a struct and returns the struct.
+func configTarget(target any) (reflect.Value, error) {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, errors.New("configuration target must be a pointer to a struct")
+	}
+	return v.Elem(), nil
+}
+
+// configureValue registers a copy of v with Configure.
+func (c *Container) configureValue(key string, v reflect.Value) error {
+	value := reflect.New(v.Type()).Elem()
+	value.Set(v)
+
+	fnType := reflect.FuncOf(nil, []reflect.Type{v.Type()}, false)
+	constructor := reflect.MakeFunc(fnType, func([]reflect.Value) []reflect.Value {
+		return []reflect.Value{value}
+	})
+
+	return c.Configure(key, constructor.Interface())
+}
+
+// fillFromEnv sets the fields of the struct v from the environment variables.
+func fillFromEnv(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("env"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = snakeCase(field.Name)
+		}
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+
+		if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+			if err := fillFromEnv(v.Field(i), name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			if options == "required" {
+				return fmt.Errorf("missing environment variable %s", name)
+			}
+			continue
+		}
+
+		if err := setString(v.Field(i), value); err != nil {
+			return fmt.Errorf("environment variable %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// setString parses s into v, according to the kind of v.
+func setString(v reflect.Value, s string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		parts := strings.Split(s, ",")
+		slice := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setString(slice.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	default:
+		return fmt.Errorf("unsupported type %v", v.Type())
+	}
+	return nil
+}
+
+// snakeCase converts a Go identifier, like MaxConns or DBURL, to MAX_CONNS or DBURL.
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var sb strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteRune(unicode.ToUpper(r))
+	}
+	return sb.String()
+}
@@ -0,0 +1,107 @@
+package cosmo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type PoolConfig struct {
+	Size int
+}
+
+type EnvDBConfig struct {
+	URL      string `env:"URL,required"`
+	MaxConns int
+	Timeout  time.Duration
+	Replicas []string
+	ReadOnly bool `json:"read_only"`
+	Pool     PoolConfig
+}
+
+func TestConfigureFromEnv(t *testing.T) {
+	t.Setenv("DB_URL", DBURL)
+	t.Setenv("DB_TIMEOUT", "5s")
+	t.Setenv("DB_REPLICAS", "a.db, b.db")
+	t.Setenv("DB_POOL_SIZE", "4")
+
+	c := New()
+	err := c.ConfigureFromEnv("DB", &EnvDBConfig{MaxConns: 10})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = c.Invoke(func(cfg EnvDBConfig) {
+		if cfg.URL != DBURL || cfg.Timeout != 5*time.Second || cfg.MaxConns != 10 || cfg.Pool.Size != 4 {
+			t.Errorf("wrong configuration %+v", cfg)
+		}
+		if len(cfg.Replicas) != 2 || cfg.Replicas[1] != "b.db" {
+			t.Errorf("wrong slice %v", cfg.Replicas)
+		}
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	if _, ok := c.Get("DB").(EnvDBConfig); !ok {
+		t.Error("configuration was not registered under its key")
+	}
+}
+
+func TestConfigureFromEnvErrors(t *testing.T) {
+	c := New()
+	if err := c.ConfigureFromEnv("MISSING", &EnvDBConfig{}); err == nil {
+		t.Error("missing required variable did not return error")
+	}
+
+	t.Setenv("BAD_URL", DBURL)
+	t.Setenv("BAD_MAX_CONNS", "ten")
+	if err := c.ConfigureFromEnv("BAD", &EnvDBConfig{}); err == nil {
+		t.Error("invalid number did not return error")
+	}
+
+	if err := c.ConfigureFromEnv("DB", EnvDBConfig{}); err == nil {
+		t.Error("target that is not a pointer was accepted")
+	}
+}
+
+func TestConfigureFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.json")
+	os.WriteFile(path, []byte(`{"URL": "sqlite://file.db", "MaxConns": 3, "read_only": true}`), 0o600)
+	t.Setenv("DB_URL", DBURL)
+
+	c := New()
+	cfg := &EnvDBConfig{}
+	if err := c.ConfigureFromFile("DB", path, cfg); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := c.ConfigureFromEnv("DB", cfg); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	c.Invoke(func(cfg EnvDBConfig) {
+		if cfg.URL != DBURL || cfg.MaxConns != 3 || !cfg.ReadOnly {
+			t.Errorf("wrong configuration %+v", cfg)
+		}
+	})
+
+	if err := c.ConfigureFromFile("DB", "config.toml", cfg); err == nil {
+		t.Error("file without decoder was accepted")
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	cases := map[string]string{
+		"URL":      "URL",
+		"MaxConns": "MAX_CONNS",
+		"DBURL":    "DBURL",
+		"HTTPPort": "HTTP_PORT",
+		"Timeout":  "TIMEOUT",
+	}
+	for in, expected := range cases {
+		if out := snakeCase(in); out != expected {
+			t.Errorf("snakeCase(%s) = %s, expected %s", in, out, expected)
+		}
+	}
+}
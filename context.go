@@ -0,0 +1,23 @@
+package cosmo
+
+import (
+	"context"
+	"reflect"
+)
+
+var contextType = reflect.TypeFor[context.Context]()
+
+// InvokeCtx runs the function like Container.Invoke, but the arguments of type
+// context.Context, in the function and in the constructors of its dependencies,
+// receive ctx instead of the container context.
+//
+//	err := c.InvokeCtx(r.Context(), func(ctx context.Context, repo UserRepository) error {
+//		return repo.Save(ctx, user)
+//	})
+//
+// Singletons keep the context they were built with, so constructors of singletons
+// shouldn't retain request contexts.
+func (c *Container) InvokeCtx(ctx context.Context, fn any) error {
+	_, err := c.invoke(ctx, fn, Arguments{})
+	return err
+}
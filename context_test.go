@@ -0,0 +1,57 @@
+package cosmo
+
+import (
+	"context"
+	"testing"
+)
+
+type contextKey string
+
+type ContextDBService struct {
+	Ctx context.Context
+}
+
+func (svc *ContextDBService) Get() error {
+	return svc.Ctx.Err()
+}
+
+func TestInvokeCtx(t *testing.T) {
+	c := New()
+	c.Add(func(ctx context.Context) DBService {
+		return &ContextDBService{Ctx: ctx}
+	})
+
+	ctx := context.WithValue(context.Background(), contextKey("request"), "42")
+	err := c.InvokeCtx(ctx, func(fnCtx context.Context, db DBService) {
+		if fnCtx.Value(contextKey("request")) != "42" {
+			t.Error("caller context was not injected into the function")
+		}
+		if db.(*ContextDBService).Ctx.Value(contextKey("request")) != "42" {
+			t.Error("caller context was not injected into the constructor")
+		}
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = c.InvokeCtx(cancelled, func(db DBService) error {
+		return db.Get()
+	})
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestInvokeDefaultContext(t *testing.T) {
+	c := New()
+	err := c.Invoke(func(ctx context.Context) {
+		if ctx.Value(ContextKey) != c {
+			t.Error("container context was not injected")
+		}
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
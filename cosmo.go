@@ -4,7 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Scope is a dependency scope
@@ -19,13 +24,70 @@ const ContextKey ContextKeyValue = "Cosmo:Container"
 const (
 	ScopeTransient Scope = iota
 	ScopeSingleton
+	// ScopeExpiring is the scope of the providers added with AddExpiring, whose
+	// instances are cached until they expire.
+	ScopeExpiring
 )
 
+// String returns the name of the scope, as accepted by ParseScope, except for
+// ScopeExpiring, which needs an ExpiryPolicy.
+func (s Scope) String() string {
+	switch s {
+	case ScopeTransient:
+		return "transient"
+	case ScopeSingleton:
+		return "singleton"
+	case ScopeExpiring:
+		return "expiring"
+	}
+	return fmt.Sprintf("Scope(%d)", int(s))
+}
+
+// ParseScope returns the scope with the name s.
+func ParseScope(s string) (Scope, error) {
+	switch s {
+	case "transient":
+		return ScopeTransient, nil
+	case "singleton":
+		return ScopeSingleton, nil
+	}
+	return 0, fmt.Errorf("unknown scope %q", s)
+}
+
 // Container manages the configurations, providers and instances
 type Container struct {
 	configurations map[string]reflect.Type
 	providers      map[reflect.Type]Spec
-	instances      map[reflect.Type]reflect.Value
+	groups         map[string]*group
+	named          map[string]Spec
+	decorators     map[reflect.Type][]reflect.Value
+	cache          atomic.Pointer[cache]
+	generation     atomic.Uint64
+	registrations  atomic.Uint64
+	mu             sync.Mutex
+	closers        []func()
+	rebuildMu      sync.Mutex
+	rebuilding     atomic.Pointer[rebuildGate]
+	metrics        Metrics
+	tracer         Tracer
+	panicPolicy    PanicPolicy
+	redactors      map[reflect.Type]Redactor
+	secretFields   map[reflect.Type]map[string]bool
+	middlewares    []ResolveMiddleware
+	resolver       ResolveFunc
+	parent         *Container
+	module         string
+	fallbacks      map[reflect.Type]Spec
+	degraded       []DegradedModule
+	background     []backgroundInit
+	sealed         atomic.Bool
+	shared         atomic.Bool
+	conditional    map[reflect.Type][]Spec
+	profiles       map[string]bool
+	tunable        map[string]tunable
+	aliases        map[reflect.Type]reflect.Type
+	adaptPointers  bool
+	setters        map[reflect.Type][]string
 }
 
 // Spec is a descriptor of the service providers
@@ -33,15 +95,42 @@ type Spec struct {
 	Type  reflect.Type
 	Value reflect.Value
 	Scope Scope
+	// Name is the name of the provider, if it was added with a name.
+	Name string
+	// Group is the name of the group of the provider, if it was added to a group.
+	Group string
+
+	ready    *readiness
+	expiry   *ExpiryPolicy
+	profiles []string
+	args     Arguments
+	index    int
+	order    uint64
+	module   string
 }
 
 // New creates a new Container
 func New() *Container {
-	return &Container{
+	c := &Container{
 		configurations: make(map[string]reflect.Type),
 		providers:      make(map[reflect.Type]Spec),
-		instances:      make(map[reflect.Type]reflect.Value),
+		groups:         make(map[string]*group),
+		named:          make(map[string]Spec),
+		decorators:     make(map[reflect.Type][]reflect.Value),
+		redactors:      make(map[reflect.Type]Redactor),
+		secretFields:   make(map[reflect.Type]map[string]bool),
+		fallbacks:      make(map[reflect.Type]Spec),
+		conditional:    make(map[reflect.Type][]Spec),
+		profiles:       make(map[string]bool),
+		tunable:        make(map[string]tunable),
+		aliases:        make(map[reflect.Type]reflect.Type),
+		setters:        make(map[reflect.Type][]string),
+		metrics:        nopMetrics{},
+		tracer:         nopTracer{},
 	}
+	c.resolver = c.resolveType
+	c.cache.Store(newCache())
+	return c
 }
 
 // Context creates a context that contains this container. Dependencies can later
@@ -51,19 +140,45 @@ func (c *Container) Context() context.Context {
 }
 
 // AddWithScope will add the constructor to the providers using the specified scope.
+//
+// A constructor returning many values, like func() (*Reader, *Writer, error), is
+// registered as the provider of each of them. With ScopeSingleton the constructor
+// is called once and all its values are cached.
 func (c *Container) AddWithScope(scope Scope, constructor any) error {
-	t, v, err := spec(constructor)
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	if err := checkScope(scope); err != nil {
+		return err
+	}
+	_, v, err := spec(constructor)
 	if err != nil {
 		return err
 	}
-	c.providers[t] = Spec{
-		Type:  t,
-		Value: v,
-		Scope: scope,
+
+	for _, provider := range c.specs(scope, v) {
+		c.providers[provider.Type] = provider
 	}
 	return nil
 }
 
+// specs returns a Spec for each value built by the constructor v.
+func (c *Container) specs(scope Scope, v reflect.Value) []Spec {
+	products, _, _, _ := results(v.Type())
+	specs := make([]Spec, products)
+	for i := range specs {
+		specs[i] = Spec{
+			Type:   v.Type().Out(i),
+			Value:  v,
+			Scope:  scope,
+			index:  i,
+			order:  c.registrations.Add(1),
+			module: c.module,
+		}
+	}
+	return specs
+}
+
 // Add adds the constructor to the container with ScopeTransient
 func (c *Container) Add(constructor any) error {
 	if err := c.AddWithScope(ScopeTransient, constructor); err != nil {
@@ -80,6 +195,11 @@ func (c *Container) AddSingleton(constructor any) error {
 	return nil
 }
 
+// Provider returns the Spec registered for the type and whether it exists.
+func (c *Container) Provider(t reflect.Type) (Spec, bool) {
+	return c.provider(t)
+}
+
 // spec uses reflect to identify the type and value of the constructor, also performs
 // validation to know if the constructor is a function and has the correct amount of
 // output types.
@@ -88,102 +208,401 @@ func spec(constructor any) (reflect.Type, reflect.Value, error) {
 	t := v.Type()
 
 	if t.Kind() != reflect.Func {
-		return t, reflect.Value{}, errors.New("constructor must be a function")
+		return t, reflect.Value{}, fmt.Errorf("%w: must be a function, got %v", ErrInvalidConstructor, t)
 	}
 
-	if t.NumOut() == 0 || t.NumOut() > 2 {
-		return t, reflect.Value{}, errors.New("constructor must return T or (T, error)")
+	if _, _, _, ok := results(t); !ok {
+		return t, reflect.Value{}, fmt.Errorf("%w: must return T, (T, error), (T, func()) or (T, func(), error), where T can be many values of different types, got %v", ErrInvalidConstructor, t)
 	}
 
 	return t.Out(0), v, nil
 }
 
-// resolve returns the instance associated with the type passed as argument.
+// results validates the values returned by the constructor type t, returning how
+// many values it builds and the position of the error and of the cleanup function,
+// or -1 if the constructor doesn't return them. The values being built come first,
+// each of a different type, and they can be followed by an error and a func(), in
+// any order.
+func results(t reflect.Type) (products, errIndex, cleanupIndex int, ok bool) {
+	errIndex, cleanupIndex = -1, -1
+
+	seen := make(map[reflect.Type]bool)
+	for products < t.NumOut() {
+		out := t.Out(products)
+		if out == errorType || out == cleanupType || seen[out] {
+			break
+		}
+		seen[out] = true
+		products++
+	}
+	if products == 0 {
+		return products, errIndex, cleanupIndex, false
+	}
+
+	for i := products; i < t.NumOut(); i++ {
+		switch {
+		case t.Out(i) == errorType && errIndex < 0:
+			errIndex = i
+		case t.Out(i) == cleanupType && cleanupIndex < 0:
+			cleanupIndex = i
+		default:
+			return products, errIndex, cleanupIndex, false
+		}
+	}
+
+	return products, errIndex, cleanupIndex, true
+}
+
+// resolve returns the instance associated with the type passed as argument, going
+// through the resolve middlewares registered in the container.
+func (c *Container) resolve(ctx context.Context, t reflect.Type) (reflect.Value, error) {
+	v, err := c.resolver(ctx, t)
+	c.metrics.Resolved(t, err)
+	return v, err
+}
+
+// resolveType returns the instance associated with the type passed as argument.
 //
 // If the dependency was registered with ScopeSingleton, then resolve will first
 // check if the instance already exists, if it does, resolve won't call the ctor again.
 //
 // If the instance was not created before, resolve creates the instance and stores in cache
 // to reuse it later.
-func (c *Container) resolve(t reflect.Type) (reflect.Value, error) {
-	if inst, ok := c.instances[t]; ok {
+func (c *Container) resolveType(ctx context.Context, t reflect.Type) (reflect.Value, error) {
+	if t == contextType {
+		return reflect.ValueOf(&ctx).Elem(), nil
+	}
+
+	if err := c.waitRebuild(ctx, t); err != nil {
+		return reflect.Value{}, err
+	}
+
+	if inst, ok := c.cacheOf(ctx).get(t); ok {
+		c.metrics.CacheHit(t)
 		return inst, nil
 	}
 
-	provider, ok := c.providers[t]
+	provider, ok := c.provider(t)
 	if !ok {
-		return reflect.Value{}, fmt.Errorf("no provider for type %v", t)
+		if reflect.PointerTo(t).Implements(optionalType) {
+			return c.resolveOptional(ctx, t)
+		}
+		if reflect.PointerTo(t).Implements(lazyType) {
+			return c.resolveLazy(ctx, t), nil
+		}
+		if v, found, err := c.resolveAdapted(ctx, t); found {
+			return v, err
+		}
+		if c.parent != nil && !c.hasLocal(t) && c.parent.HasProvider(t) {
+			return c.parent.resolve(ctx, t)
+		}
+		if t.Kind() == reflect.Slice {
+			if v, found, err := c.resolveGroups(ctx, t.Elem()); found {
+				return v, err
+			}
+		}
+		if isThunk(t) {
+			return c.resolveThunk(ctx, t), nil
+		}
+		return reflect.Value{}, &NoProviderError{Type: t, Chain: Chain(ctx), Profiles: c.inactiveProfiles(t)}
 	}
 
-	providerType := provider.Value.Type()
-	args := make([]reflect.Value, providerType.NumIn())
+	if provider.expiry != nil {
+		return c.resolveExpiring(ctx, provider)
+	}
+	if provider.Scope == ScopeSingleton {
+		return c.resolveSingleton(ctx, t, provider)
+	}
 
-	for i := 0; i < providerType.NumIn(); i++ {
-		argType := providerType.In(i)
-		val, err := c.resolve(argType)
-		if err != nil {
+	return c.call(ctx, provider)
+}
+
+// resolveSingleton builds and caches the singleton of the provider.
+func (c *Container) resolveSingleton(ctx context.Context, t reflect.Type, provider Spec) (reflect.Value, error) {
+	// Values built by the same constructor share the flight, since building one
+	// caches the others.
+	var key any = t
+	if planOf(provider.Value.Type()).products > 1 {
+		key = provider.Value
+	}
+	return c.buildOnce(ctx, t, key, provider)
+}
+
+// buildOnce returns the singleton cached in slot, building it with the provider
+// when it's not cached yet. Concurrent resolutions of the same key wait for the one
+// building it, so the constructor runs once.
+func (c *Container) buildOnce(ctx context.Context, slot, key any, provider Spec) (reflect.Value, error) {
+	// The cycles are detected before waiting for the singleton, which would deadlock.
+	if _, err := withChain(ctx, provider); err != nil {
+		return reflect.Value{}, err
+	}
+
+	cache := c.cacheOf(ctx)
+	for {
+		inst, cached, f, leader := cache.join(slot, key)
+		if cached {
+			c.metrics.CacheHit(provider.Type)
+			return inst, nil
+		}
+		if leader {
+			landed := false
+			defer func() {
+				// The constructor panicked with PanicFailFast.
+				if !landed {
+					cache.land(key, f, fmt.Errorf("constructor of %v panicked", provider.Type))
+				}
+			}()
+
+			result, err := c.call(ctx, provider)
+			if err == nil {
+				cache.store(slot, result)
+			}
+			landed = true
+			cache.land(key, f, err)
+			return result, err
+		}
+
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			return reflect.Value{}, ctx.Err()
+		}
+		if f.err != nil {
+			return reflect.Value{}, f.err
+		}
+	}
+}
+
+// call resolves the arguments of the provider constructor and calls it, returning
+// the constructed value or the error returned by the constructor.
+func (c *Container) call(ctx context.Context, provider Spec) (_ reflect.Value, err error) {
+	ctx, err = withChain(ctx, provider)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if provider.ready != nil {
+		if err := provider.ready.wait(ctx, provider.Type); err != nil {
 			return reflect.Value{}, err
 		}
+	}
 
-		args[i] = val
+	ctx, end := c.startSpan(ctx, provider.Type.String)
+	defer func() { end(err) }()
+
+	p := planOf(provider.Value.Type())
+	args, err := c.resolveArgsWith(ctx, p, provider.args)
+	if err != nil {
+		return reflect.Value{}, err
 	}
 
-	out := provider.Value.Call(args)
+	var start time.Time
+	if _, nop := c.metrics.(nopMetrics); !nop {
+		start = time.Now()
+	}
+	out, err := c.construct(ctx, provider.Type, provider.Value, args)
 
-	if len(out) == 2 && !out[1].IsNil() {
-		return reflect.Value{}, out[1].Interface().(error)
+	if err == nil && p.errIndex > 0 && !out[p.errIndex].IsNil() {
+		err = &ConstructorError{
+			Type:  provider.Type,
+			Chain: Chain(ctx),
+			Err:   out[p.errIndex].Interface().(error),
+		}
 	}
 
-	result := out[0]
+	if !start.IsZero() {
+		c.metrics.Constructed(provider.Type, time.Since(start), err)
+	}
+	if err != nil {
+		return reflect.Value{}, err
+	}
 
-	if provider.Scope == ScopeSingleton {
-		c.instances[t] = result
+	// Transient instances aren't kept, so recording their cleanups would grow the
+	// list on every resolution.
+	if provider.Scope != ScopeTransient {
+		if p.cleanupIndex > 0 && !out[p.cleanupIndex].IsNil() {
+			fn := out[p.cleanupIndex].Interface().(func())
+			c.cacheOf(ctx).addCleanup(provider, func() error {
+				fn()
+				return nil
+			})
+		}
+
+		if provider.Group != "" {
+			if closer, ok := out[0].Interface().(io.Closer); ok {
+				c.cacheOf(ctx).addCleanup(provider, closer.Close)
+			}
+		}
 	}
 
-	return result, nil
+	if p.products > 1 && provider.Scope == ScopeSingleton && provider.Name == "" && provider.Group == "" {
+		if err := c.cacheSiblings(ctx, provider, out[:p.products]); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	if err := c.inject(ctx, provider.Type, out[provider.index]); err != nil {
+		return reflect.Value{}, &ConstructorError{Type: provider.Type, Chain: Chain(ctx), Err: err}
+	}
+
+	return c.decorate(ctx, provider.Type, out[provider.index])
 }
 
 // Invoke runs a function, injecting the dependencies in the function arguments.
 // This method uses reflection to identify the function arguments types, so it can
 // know which types to resolve.
+//
+// If the last value returned by the function is an error, Invoke returns it.
+//
+// Arguments of type context.Context receive the container context, use
+// Container.InvokeCtx to pass a different context.
 func (c *Container) Invoke(fn any) error {
+	_, err := c.invoke(c.Context(), fn, Arguments{})
+	return err
+}
+
+// InvokeResult runs the function like Container.Invoke, returning the first value
+// returned by the function. The function must return T or (T, error).
+//
+//	user, err := cosmo.InvokeResult[User](c, func(db DBService) (User, error) {
+//		return db.FindUser(id)
+//	})
+func InvokeResult[T any](c *Container, fn any) (T, error) {
+	var result T
+
+	out, err := c.invoke(c.Context(), fn, Arguments{})
+	if err != nil {
+		return result, err
+	}
+
+	if len(out) == 0 {
+		return result, errors.New("invoked function has no return values")
+	}
+
+	result, ok := out[0].Interface().(T)
+	if !ok && out[0].Interface() != nil {
+		return result, fmt.Errorf("invoked function returned %v, expected %v", out[0].Type(), reflect.TypeFor[T]())
+	}
+
+	return result, nil
+}
+
+var errorType = reflect.TypeFor[error]()
+
+// invoke resolves the arguments of fn, other than the supplied ones, and calls it. It
+// returns the values returned by fn, without the trailing error, which is returned
+// as the error result.
+func (c *Container) invoke(ctx context.Context, fn any, supplied Arguments) (out []reflect.Value, err error) {
 	v := reflect.ValueOf(fn)
 	if v.Kind() != reflect.Func {
-		return errors.New("invoke expects a function")
+		return nil, errors.New("invoke expects a function")
 	}
 
 	t := v.Type()
-	args := make([]reflect.Value, t.NumIn())
+	ctx, end := c.startSpan(ctx, func() string { return "invoke " + t.String() })
+	defer func() { end(err) }()
 
-	for i := 0; i < t.NumIn(); i++ {
-		argType := t.In(i)
-		val, err := c.resolve(argType)
-		if err != nil {
-			return err
-		}
-		args[i] = val
+	args, err := c.resolveArgsWith(ctx, planOf(t), supplied)
+	if err != nil {
+		return nil, err
 	}
 
-	v.Call(args)
+	out = callFunc(v, args)
 
-	return nil
+	if n := len(out); n > 0 && t.Out(n-1) == errorType {
+		if err := out[n-1]; !err.IsNil() {
+			return nil, err.Interface().(error)
+		}
+		out = out[:n-1]
+	}
+
+	return out, nil
 }
 
 // Bind injects dependencies into the `out` struct.
 // `out` must be a pointer to a struct.
-// All dependencies inside the out struct will be resolved using the
-// current cosmo.Container, and will return error if they can't.
+// All exported dependencies inside the out struct will be resolved using the
+// current cosmo.Container, and will return error if they can't. Unexported
+// fields are ignored.
+//
+// Embedded structs without a provider have their fields bound too, like the fields
+// tagged with `cosmo:"inline"`, which allows binding a tree of structs in one call:
+//
+//	type Handlers struct {
+//		Users  UserHandlers  `cosmo:"inline"`
+//		Orders *OrderHandlers `cosmo:"inline"`
+//	}
+//
+// Nil pointers to inline structs are allocated.
+//
+// The resolution of each field can be changed with the `cosmo` struct tag:
+//
+//   - `cosmo:"-"` skips the field
+//   - `cosmo:"name=replica"` resolves the provider added with AddNamed or Configure
+//   - `cosmo:"group=validators"` resolves the members of the group
+//   - `cosmo:"optional"` leaves the field untouched if there is no provider
+//   - `cosmo:"inline"` binds the fields of the struct instead of resolving it
 func (c *Container) Bind(out any) error {
-	v := reflect.ValueOf(out).Elem()
+	ptr := reflect.ValueOf(out)
+	if ptr.Kind() != reflect.Pointer || ptr.Elem().Kind() != reflect.Struct {
+		return errors.New("bind expects a pointer to a struct")
+	}
+
+	return c.bindStruct(c.Context(), ptr.Elem(), nil)
+}
+
+// bindStruct binds the fields of the struct v. Parents holds the types of the
+// structs v is inlined in, to detect recursive structs.
+func (c *Container) bindStruct(ctx context.Context, v reflect.Value, parents []reflect.Type) error {
 	t := v.Type()
+	if slices.Contains(parents, t) {
+		return fmt.Errorf("can't bind recursive struct %v", t)
+	}
+	parents = append(parents, t)
 
 	for i := 0; i < v.NumField(); i++ {
 		field := v.Field(i)
 		fieldType := t.Field(i)
-		val, err := c.resolve(fieldType.Type)
+
+		tag, err := parseTag(fieldType)
 		if err != nil {
 			return err
 		}
+		if tag.skip || !fieldType.IsExported() && !fieldType.Anonymous {
+			continue
+		}
+
+		if tag.inline || c.isEmbeddedStruct(fieldType, tag) {
+			if field.Kind() == reflect.Pointer {
+				if field.IsNil() && !field.CanSet() {
+					continue
+				}
+				if field.IsNil() {
+					field.Set(reflect.New(fieldType.Type.Elem()))
+				}
+				field = field.Elem()
+			}
+			if err := c.bindStruct(ctx, field, parents); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !fieldType.IsExported() {
+			continue
+		}
+
+		val, found, err := c.resolveField(ctx, fieldType.Type, tag)
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+
+		if !val.Type().AssignableTo(fieldType.Type) {
+			return fmt.Errorf("can't assign %v to field %s of type %v", val.Type(), fieldType.Name, fieldType.Type)
+		}
 
 		field.Set(val)
 	}
@@ -191,9 +610,70 @@ func (c *Container) Bind(out any) error {
 	return nil
 }
 
+// isEmbeddedStruct reports whether the field is an embedded struct, or an exported
+// pointer to one, whose fields are bound because it has no provider.
+func (c *Container) isEmbeddedStruct(field reflect.StructField, tag fieldTag) bool {
+	if !field.Anonymous || tag.name != "" || tag.group != "" || c.HasProvider(field.Type) {
+		return false
+	}
+	t := field.Type
+	if t.Kind() == reflect.Pointer && field.IsExported() {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// BindT allocates a T, binds it like Container.Bind and returns it. T is a struct or
+// a pointer to a struct.
+//
+//	deps, err := cosmo.BindT[HandlerDeps](c)
+func BindT[T any](c *Container) (T, error) {
+	var v T
+
+	t := reflect.TypeFor[T]()
+	if t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct {
+		ptr := reflect.New(t.Elem())
+		if err := c.Bind(ptr.Interface()); err != nil {
+			return v, err
+		}
+		return ptr.Interface().(T), nil
+	}
+
+	err := c.Bind(&v)
+	return v, err
+}
+
+// resolveField resolves the value of a struct field according to its tag. The
+// boolean result is false when an optional field has no provider.
+func (c *Container) resolveField(ctx context.Context, t reflect.Type, tag fieldTag) (reflect.Value, bool, error) {
+	switch {
+	case tag.name != "":
+		if tag.optional && !c.hasNamed(tag.name) {
+			return reflect.Value{}, false, nil
+		}
+		v, err := c.resolveNamed(ctx, tag.name)
+		return v, err == nil, err
+	case tag.group != "":
+		if _, ok := c.groups[tag.group]; tag.optional && !ok {
+			return reflect.Value{}, false, nil
+		}
+		v, err := c.resolveGroup(ctx, tag.group)
+		return v, err == nil, err
+	}
+
+	if tag.optional && !c.HasProvider(t) {
+		return reflect.Value{}, false, nil
+	}
+	v, err := c.resolve(ctx, t)
+	return v, err == nil, err
+}
+
 // Configure sets the constructor in a configurations map, so it can be retrieved
 // later using the associated key
 func (c *Container) Configure(key string, constructor any) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
 	t, _, err := spec(constructor)
 	if err != nil {
 		return err
@@ -208,14 +688,15 @@ func (c *Container) Configure(key string, constructor any) error {
 	return nil
 }
 
-// Get returns the resolved type associated with the key
+// Get returns the resolved type associated with the key. Children, created with
+// Container.Child, resolve the keys configured in their parent too.
 func (c *Container) Get(key string) any {
-	t, ok := c.configurations[key]
+	t, ok := c.configuration(key)
 	if !ok {
 		return nil
 	}
 
-	v, err := c.resolve(t)
+	v, err := c.resolve(c.Context(), t)
 	if err != nil {
 		return nil
 	}
@@ -223,6 +704,17 @@ func (c *Container) Get(key string) any {
 	return v.Interface()
 }
 
+// configuration returns the type configured under key in the container or its
+// parents.
+func (c *Container) configuration(key string) (reflect.Type, bool) {
+	for ; c != nil; c = c.parent {
+		if t, ok := c.configurations[key]; ok {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
 // Context returns the resolved type associated with key. It uses *Container.Get
 // after obtaining the container inside the context. This is just a helper function, the
 // container can be retrieved by using:
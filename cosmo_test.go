@@ -1,6 +1,7 @@
 package cosmo
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -161,3 +162,114 @@ func ExampleContainer() {
 	}
 	service.Get()
 }
+
+func TestInvokeError(t *testing.T) {
+	c := New()
+	c.Add(func() DBService {
+		return &SQLDBService{}
+	})
+
+	expected := errors.New("invoke failed")
+	err := c.Invoke(func(db DBService) error {
+		return expected
+	})
+	if !errors.Is(err, expected) {
+		t.Errorf("invoke returned %v instead of the function error", err)
+	}
+
+	if err = c.Invoke(func(db DBService) error { return db.Get() }); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestInvokeResult(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() Config {
+		return Config{
+			URL: DBURL,
+		}
+	})
+
+	url, err := InvokeResult[string](c, func(cfg Config) (string, error) {
+		return cfg.URL, nil
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	if url != DBURL {
+		t.Errorf("wrong value returned from InvokeResult: %s", url)
+	}
+
+	svc, err := InvokeResult[DBService](c, func(cfg Config) *SQLDBService {
+		return &SQLDBService{Config: cfg}
+	})
+	if err != nil || svc == nil {
+		t.Error("could not convert the returned value to an interface")
+	}
+
+	if _, err = InvokeResult[int](c, func(cfg Config) string { return cfg.URL }); err == nil {
+		t.Error("InvokeResult accepted a value of the wrong type")
+	}
+}
+
+func TestBindT(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() Config { return Config{URL: DBURL} })
+	c.Add(func(cfg Config) DBService { return &SQLDBService{Config: cfg} })
+
+	deps, err := BindT[ToBind](c)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if deps.DB == nil {
+		t.Error("BindT did not bind the struct")
+	}
+
+	ptr, err := BindT[*ToBind](c)
+	if err != nil || ptr == nil || ptr.DB == nil {
+		t.Error("BindT did not allocate the pointer")
+	}
+
+	if _, err = BindT[string](c); err == nil {
+		t.Error("BindT accepted a type that is not a struct")
+	}
+}
+
+type Reader struct{ pipe *[]string }
+type Writer struct{ pipe *[]string }
+
+func TestMultipleResults(t *testing.T) {
+	c := New()
+	calls, cleanups := 0, 0
+
+	err := c.AddSingleton(func() (*Reader, *Writer, func(), error) {
+		calls++
+		pipe := &[]string{}
+		return &Reader{pipe: pipe}, &Writer{pipe: pipe}, func() { cleanups++ }, nil
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = c.Invoke(func(w *Writer, r *Reader) {
+		if w.pipe != r.pipe {
+			t.Error("values were built by different calls")
+		}
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	c.Invoke(func(r *Reader) {})
+	if calls != 1 {
+		t.Errorf("singleton constructor was called %d times", calls)
+	}
+
+	c.Close()
+	if cleanups != 1 {
+		t.Errorf("cleanup ran %d times", cleanups)
+	}
+
+	if err := c.Add(func() (*Reader, *Reader) { return nil, nil }); err == nil {
+		t.Error("constructor returning the same type twice was accepted")
+	}
+}
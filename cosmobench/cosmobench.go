@@ -0,0 +1,70 @@
+// Package cosmobench helps measuring the cost of resolving real dependency graphs,
+// so it can be compared across versions of cosmo and of the application wiring.
+package cosmobench
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gustavosvalentim/cosmo"
+)
+
+// Resolve benchmarks the resolution of root from c. Root is given as accepted by
+// cosmo.TypeOf. The root is resolved once before the timer starts, so singletons
+// are already built and only the steady state resolution cost is measured.
+//
+//	func BenchmarkHandler(b *testing.B) {
+//		c := app.NewContainer()
+//		cosmobench.Resolve(b, c, (*http.Handler)(nil))
+//	}
+func Resolve(b *testing.B, c *cosmo.Container, root any) {
+	b.Helper()
+
+	fn := invoker(cosmo.TypeOf(root))
+	if err := c.Invoke(fn); err != nil {
+		b.Fatalf("resolving %v: %v", cosmo.TypeOf(root), err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := c.Invoke(fn); err != nil {
+			b.Fatalf("resolving %v: %v", cosmo.TypeOf(root), err)
+		}
+	}
+}
+
+// ResolveCold benchmarks the resolution of root from a new container, built by
+// newContainer, on every iteration. It measures the cost of a cold start, including
+// the construction of singletons. Building the container is not measured.
+func ResolveCold(b *testing.B, newContainer func() *cosmo.Container, root any) {
+	b.Helper()
+
+	fn := invoker(cosmo.TypeOf(root))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		c := newContainer()
+		b.StartTimer()
+
+		if err := c.Invoke(fn); err != nil {
+			b.Fatalf("resolving %v: %v", cosmo.TypeOf(root), err)
+		}
+
+		b.StopTimer()
+		c.Close()
+		b.StartTimer()
+	}
+}
+
+// invoker returns a function that receives a value of type t and does nothing.
+func invoker(t reflect.Type) any {
+	fnType := reflect.FuncOf([]reflect.Type{t}, nil, false)
+	return reflect.MakeFunc(fnType, func([]reflect.Value) []reflect.Value {
+		return nil
+	}).Interface()
+}
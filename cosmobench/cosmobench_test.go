@@ -0,0 +1,42 @@
+package cosmobench
+
+import (
+	"testing"
+
+	"github.com/gustavosvalentim/cosmo"
+)
+
+type Config struct {
+	URL string
+}
+
+type Repository interface {
+	Find(id int) string
+}
+
+type SQLRepository struct {
+	Config Config
+}
+
+func (r *SQLRepository) Find(id int) string {
+	return r.Config.URL
+}
+
+func newContainer() *cosmo.Container {
+	c := cosmo.New()
+	c.AddSingleton(func() Config {
+		return Config{URL: "sqlite://bench.db"}
+	})
+	c.Add(func(cfg Config) Repository {
+		return &SQLRepository{Config: cfg}
+	})
+	return c
+}
+
+func BenchmarkResolve(b *testing.B) {
+	Resolve(b, newContainer(), (*Repository)(nil))
+}
+
+func BenchmarkResolveCold(b *testing.B) {
+	ResolveCold(b, newContainer, (*Repository)(nil))
+}
@@ -0,0 +1,629 @@
+// Package cosmogen generates plain Go code that wires the providers registered in a
+// cosmo.Container without reflection.
+//
+// The registrations only exist in the application, so the generator is a small
+// program of the application that builds its container and calls Generate:
+//
+//	//go:generate go run ./internal/wiregen
+//
+//	func main() {
+//		c := app.NewContainer()
+//		f, _ := os.Create("wiring/wiring_gen.go")
+//		defer f.Close()
+//		if err := cosmogen.Generate(f, c, cosmogen.Options{Package: "wiring"}); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+//
+// The generated Container has one method for each provider, named after the type,
+// named provider or group it builds, and a Close method running the cleanups. Missing
+// dependencies and cycles are reported by Generate, and the generated code is checked
+// by the compiler, so production builds keep cosmo's registrations without paying
+// for reflect.Call.
+//
+// Constructors and decorators must be top-level functions of importable packages.
+// Optional, Lazy and function dependencies, and readiness gates, are not supported.
+package cosmogen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gustavosvalentim/cosmo"
+)
+
+// Options configures the generated code.
+type Options struct {
+	// Package is the name of the package of the generated file.
+	Package string
+	// Type is the name of the generated container type, defaults to "Container".
+	Type string
+}
+
+// Generate writes to w the Go source of a container building the providers of c.
+func Generate(w io.Writer, c *cosmo.Container, opts Options) error {
+	if opts.Package == "" {
+		return errors.New("cosmogen: package name is required")
+	}
+	if opts.Type == "" {
+		opts.Type = "Container"
+	}
+
+	g := &generator{
+		c:       c,
+		opts:    opts,
+		imports: make(map[string]string),
+		used:    make(map[string]bool),
+		byType:  make(map[reflect.Type]*node),
+		groups:  make(map[string][]*node),
+		slices:  make(map[reflect.Type]string),
+		names:   make(map[string]bool),
+
+		groupBuilders: make(map[string]string),
+	}
+	for _, local := range []string{"c", "v", "err", "cleanup", "closer", "members"} {
+		g.used[local] = true
+	}
+	// Builders of types named like the fields of the container would clash with
+	// them, and accessors named like well-known methods would confuse go vet.
+	for _, name := range []string{"buildMu", "buildCleanups", "Close", "String", "Error", "Format"} {
+		g.names[name] = true
+	}
+
+	src, err := g.generate()
+	if err != nil {
+		return fmt.Errorf("cosmogen: %w", err)
+	}
+
+	_, err = w.Write(src)
+	return err
+}
+
+var (
+	contextType = reflect.TypeFor[context.Context]()
+	errorType   = reflect.TypeFor[error]()
+	cleanupType = reflect.TypeFor[func()]()
+)
+
+// node is a provider of the container, built by a generated builder method.
+type node struct {
+	info    cosmo.ProviderInfo
+	builder string
+	field   string
+	state   int
+}
+
+type generator struct {
+	c    *cosmo.Container
+	opts Options
+
+	// imports maps package paths to their aliases, and used holds the identifiers
+	// taken by aliases and locals.
+	imports map[string]string
+	used    map[string]bool
+
+	nodes  []*node
+	byType map[reflect.Type]*node
+	groups map[string][]*node
+	// slices maps element types to the builder of the slice merging their groups,
+	// and groupBuilders maps group names to the builder of their members.
+	slices        map[reflect.Type]string
+	groupBuilders map[string]string
+	// names holds the method names of the generated container.
+	names map[string]bool
+
+	methods bytes.Buffer
+	fields  bytes.Buffer
+	resets  bytes.Buffer
+}
+
+func (g *generator) generate() ([]byte, error) {
+	for _, info := range g.c.Providers() {
+		if !info.Active {
+			continue
+		}
+		n := &node{info: info}
+		switch {
+		case info.Name != "":
+			n.builder = g.method("build" + exported(info.Name))
+		case info.Group != "":
+			n.builder = g.method("build" + exported(info.Group) + "Member")
+			g.groups[info.Group] = append(g.groups[info.Group], n)
+		default:
+			n.builder = g.method("build" + typeName(info.Type))
+			g.byType[info.Type] = n
+		}
+		g.nodes = append(g.nodes, n)
+	}
+
+	for _, n := range g.nodes {
+		if err := g.check(n); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, n := range g.nodes {
+		if err := g.accessor(n); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range sortedKeys(g.groups) {
+		if err := g.groupAccessor(name); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, n := range g.nodes {
+		if err := g.builder(n); err != nil {
+			return nil, err
+		}
+	}
+
+	return format.Source(g.file())
+}
+
+// check reports the missing dependencies and the cycles reachable from n.
+func (g *generator) check(n *node) error {
+	const (
+		visiting = 1
+		done     = 2
+	)
+
+	switch n.state {
+	case visiting:
+		return fmt.Errorf("dependency cycle through %v", n.info.Type)
+	case done:
+		return nil
+	}
+
+	n.state = visiting
+	for _, dep := range g.dependencies(n) {
+		if dep == contextType {
+			continue
+		}
+		if provider, ok := g.byType[dep]; ok {
+			if err := g.check(provider); err != nil {
+				return err
+			}
+			continue
+		}
+		if dep.Kind() != reflect.Slice || len(g.groupsOf(dep.Elem())) == 0 {
+			return fmt.Errorf("no provider for %v (needed by %s)", dep, n.info.Constructor)
+		}
+		for _, name := range g.groupsOf(dep.Elem()) {
+			for _, member := range g.groups[name] {
+				if err := g.check(member); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	n.state = done
+
+	return nil
+}
+
+// dependencies returns the arguments of the constructor and decorators of n.
+func (g *generator) dependencies(n *node) []reflect.Type {
+	var deps []reflect.Type
+	fn := n.info.Value.Type()
+	for i := 0; i < fn.NumIn(); i++ {
+		deps = append(deps, fn.In(i))
+	}
+	for _, decorator := range g.c.Decorators(n.info.Type) {
+		fn := decorator.Type()
+		for i := 0; i < fn.NumIn(); i++ {
+			if fn.In(i) != n.info.Type {
+				deps = append(deps, fn.In(i))
+			}
+		}
+	}
+	return deps
+}
+
+// groupsOf returns the names of the groups of members of type t, sorted like the
+// container merges them.
+func (g *generator) groupsOf(t reflect.Type) []string {
+	var names []string
+	for name, members := range g.groups {
+		if members[0].info.Type == t {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// accessor writes the exported method returning the instance built by n. Group
+// members are returned by the accessor of their group.
+func (g *generator) accessor(n *node) error {
+	if n.info.Group != "" {
+		return nil
+	}
+
+	typ, err := g.typeExpr(n.info.Type)
+	if err != nil {
+		return err
+	}
+
+	name := typeName(n.info.Type)
+	what := typ
+	if n.info.Name != "" {
+		name = exported(n.info.Name)
+		what = fmt.Sprintf("%s named %q", typ, n.info.Name)
+	}
+
+	g.writeAccessor(g.method(name), what, typ, n.builder)
+	return nil
+}
+
+// groupAccessor writes the builder and the exported method returning the members
+// of the group.
+func (g *generator) groupAccessor(name string) error {
+	members := g.groups[name]
+	typ, err := g.typeExpr(reflect.SliceOf(members[0].info.Type))
+	if err != nil {
+		return err
+	}
+
+	builder := g.method("build" + exported(name) + "Group")
+	g.groupBuilders[name] = builder
+	fmt.Fprintf(&g.methods, "func (c *%s) %s() (%s, error) {\n", g.opts.Type, builder, typ)
+	fmt.Fprintf(&g.methods, "var members %s\n", typ)
+	for i, member := range members {
+		fmt.Fprintf(&g.methods, "m%d, err := c.%s()\nif err != nil {\nreturn nil, err\n}\nmembers = append(members, m%d)\n", i, member.builder, i)
+	}
+	g.methods.WriteString("return members, nil\n}\n\n")
+
+	g.writeAccessor(g.method(exported(name)), fmt.Sprintf("members of the group %q", name), typ, builder)
+	return nil
+}
+
+func (g *generator) writeAccessor(name, what, typ, builder string) {
+	fmt.Fprintf(&g.methods, "// %s returns the %s.\n", name, what)
+	fmt.Fprintf(&g.methods, "func (c *%s) %s() (%s, error) {\n", g.opts.Type, name, typ)
+	g.methods.WriteString("c.mu.Lock()\ndefer c.mu.Unlock()\n")
+	fmt.Fprintf(&g.methods, "return c.%s()\n}\n\n", builder)
+}
+
+// builder writes the unexported method building n: it builds the dependencies,
+// calls the constructor and the decorators, and caches singletons.
+func (g *generator) builder(n *node) error {
+	typ, err := g.typeExpr(n.info.Type)
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "func (c *%s) %s() (v %s, err error) {\n", g.opts.Type, n.builder, typ)
+
+	switch n.info.Scope {
+	case cosmo.ScopeTransient:
+	case cosmo.ScopeSingleton:
+		n.field = strings.ToLower(n.builder[len("build"):len("build")+1]) + n.builder[len("build")+1:]
+		if token.IsKeyword(n.field) {
+			n.field += "Value"
+		}
+		fmt.Fprintf(&g.fields, "%s %s\n%sDone bool\n", n.field, typ, n.field)
+		fmt.Fprintf(&g.resets, "c.%sDone = false\n", n.field)
+		fmt.Fprintf(&b, "if c.%sDone {\nreturn c.%s, nil\n}\n", n.field, n.field)
+	default:
+		return fmt.Errorf("%s: unsupported scope %v", n.info.Constructor, n.info.Scope)
+	}
+
+	args := 0
+	if err := g.call(&b, n.info.Value, n.info.Type, -1, &args, n.field != ""); err != nil {
+		return err
+	}
+
+	// Transient instances aren't kept, so their cleanups aren't recorded, like the
+	// container does.
+	if n.info.Group != "" && n.field != "" {
+		fmt.Fprintf(&b, "if closer, ok := any(v).(%s.Closer); ok {\nc.cleanups = append(c.cleanups, func() { closer.Close() })\n}\n", g.pkg("io"))
+	}
+
+	for _, decorator := range g.c.Decorators(n.info.Type) {
+		index := -1
+		for i := 0; i < decorator.Type().NumIn(); i++ {
+			if decorator.Type().In(i) == n.info.Type {
+				index = i
+				break
+			}
+		}
+		if err := g.call(&b, decorator, n.info.Type, index, &args, n.field != ""); err != nil {
+			return err
+		}
+	}
+
+	if n.field != "" {
+		fmt.Fprintf(&b, "c.%s, c.%sDone = v, true\n", n.field, n.field)
+	}
+	b.WriteString("return v, nil\n}\n\n")
+
+	g.methods.Write(b.Bytes())
+	return nil
+}
+
+// call writes the call of the constructor or decorator fn, assigning its result
+// to v. The argument at index receives v, the others are built by the container
+// and assigned to locals numbered from *locals. The cleanup it returns is recorded
+// when keep is true, and discarded otherwise.
+func (g *generator) call(b *bytes.Buffer, fn reflect.Value, t reflect.Type, index int, locals *int, keep bool) error {
+	ref, err := g.funcRef(fn)
+	if err != nil {
+		return err
+	}
+
+	fnType := fn.Type()
+	if fnType.IsVariadic() {
+		return fmt.Errorf("%s: variadic functions are not supported", ref)
+	}
+
+	args := make([]string, fnType.NumIn())
+	for i := range args {
+		arg := fnType.In(i)
+		switch {
+		case i == index:
+			args[i] = "v"
+			continue
+		case arg == contextType:
+			args[i] = g.pkg("context") + ".Background()"
+			continue
+		}
+
+		builder, err := g.dependency(arg)
+		if err != nil {
+			return err
+		}
+		args[i] = fmt.Sprintf("a%d", *locals)
+		fmt.Fprintf(b, "%s, err := c.%s()\nif err != nil {\nreturn v, err\n}\n", args[i], builder)
+		*locals++
+	}
+
+	results := []string{"v"}
+	var returnsErr, returnsCleanup bool
+	for i := 1; i < fnType.NumOut(); i++ {
+		switch fnType.Out(i) {
+		case errorType:
+			results = append(results, "err")
+			returnsErr = true
+			continue
+		case cleanupType:
+		default:
+			return fmt.Errorf("%s: constructors returning many values are not supported", ref)
+		}
+		if !keep {
+			results = append(results, "_")
+			continue
+		}
+		results = append(results, "cleanup")
+		returnsCleanup = true
+		b.WriteString("var cleanup func()\n")
+	}
+
+	fmt.Fprintf(b, "%s = %s(%s)\n", strings.Join(results, ", "), ref, strings.Join(args, ", "))
+	if returnsErr {
+		fmt.Fprintf(b, "if err != nil {\nreturn v, %s.Errorf(\"constructor of %s failed: %%w\", err)\n}\n", g.pkg("fmt"), t)
+	}
+	if returnsCleanup {
+		b.WriteString("if cleanup != nil {\nc.cleanups = append(c.cleanups, cleanup)\n}\n")
+	}
+
+	return nil
+}
+
+// dependency returns the builder of the argument type t.
+func (g *generator) dependency(t reflect.Type) (string, error) {
+	if provider, ok := g.byType[t]; ok {
+		return provider.builder, nil
+	}
+
+	if t.Kind() != reflect.Slice {
+		return "", fmt.Errorf("no provider for %v", t)
+	}
+	if builder, ok := g.slices[t.Elem()]; ok {
+		return builder, nil
+	}
+
+	typ, err := g.typeExpr(t)
+	if err != nil {
+		return "", err
+	}
+
+	builder := g.method("build" + typeName(t.Elem()) + "Slice")
+	g.slices[t.Elem()] = builder
+
+	fmt.Fprintf(&g.methods, "func (c *%s) %s() (%s, error) {\n", g.opts.Type, builder, typ)
+	fmt.Fprintf(&g.methods, "var members %s\n", typ)
+	for i, name := range g.groupsOf(t.Elem()) {
+		fmt.Fprintf(&g.methods, "g%d, err := c.%s()\nif err != nil {\nreturn nil, err\n}\nmembers = append(members, g%d...)\n", i, g.groupBuilders[name], i)
+	}
+	g.methods.WriteString("return members, nil\n}\n\n")
+
+	return builder, nil
+}
+
+// funcRef returns the qualified name of a top-level function, importing its package.
+func (g *generator) funcRef(fn reflect.Value) (string, error) {
+	name := runtime.FuncForPC(fn.Pointer()).Name()
+
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return "", fmt.Errorf("%s is not a top-level function", name)
+	}
+
+	path := strings.ReplaceAll(name[:slash+1+dot], "%2e", ".")
+	fnName := name[slash+1+dot+1:]
+	if path == "main" || strings.ContainsAny(fnName, ".()[") {
+		return "", fmt.Errorf("%s is not a top-level function of an importable package", name)
+	}
+
+	return g.pkg(path) + "." + fnName, nil
+}
+
+// typeExpr returns the Go expression of t, importing the packages it refers to.
+func (g *generator) typeExpr(t reflect.Type) (string, error) {
+	if t.Name() != "" {
+		if strings.Contains(t.Name(), "[") {
+			return "", fmt.Errorf("generic type %v is not supported", t)
+		}
+		if t.PkgPath() == "" {
+			return t.Name(), nil
+		}
+		if t.PkgPath() == "main" {
+			return "", fmt.Errorf("type %v is not importable", t)
+		}
+		return g.pkg(t.PkgPath()) + "." + t.Name(), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice:
+		elem, err := g.typeExpr(t.Elem())
+		prefix := "*"
+		if t.Kind() == reflect.Slice {
+			prefix = "[]"
+		}
+		return prefix + elem, err
+	case reflect.Array:
+		elem, err := g.typeExpr(t.Elem())
+		return fmt.Sprintf("[%d]%s", t.Len(), elem), err
+	case reflect.Map:
+		key, err := g.typeExpr(t.Key())
+		if err != nil {
+			return "", err
+		}
+		elem, err := g.typeExpr(t.Elem())
+		return fmt.Sprintf("map[%s]%s", key, elem), err
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return "any", nil
+		}
+	}
+
+	return "", fmt.Errorf("type %v is not supported", t)
+}
+
+// pkg returns the alias of the package path, importing it.
+func (g *generator) pkg(path string) string {
+	if alias, ok := g.imports[path]; ok {
+		return alias
+	}
+
+	base := path[strings.LastIndex(path, "/")+1:]
+	base = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, base)
+	if base == "" || !unicode.IsLetter(rune(base[0])) {
+		base = "pkg" + base
+	}
+
+	alias := base
+	for i := 2; g.used[alias]; i++ {
+		alias = fmt.Sprintf("%s%d", base, i)
+	}
+
+	g.used[alias] = true
+	g.imports[path] = alias
+	return alias
+}
+
+// method returns a unique method name based on name.
+func (g *generator) method(name string) string {
+	unique := name
+	for i := 2; g.names[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	g.names[unique] = true
+	return unique
+}
+
+// file returns the unformatted source of the generated file.
+func (g *generator) file() []byte {
+	sync := g.pkg("sync")
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by cosmogen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", g.opts.Package)
+
+	b.WriteString("import (\n")
+	for _, path := range sortedKeys(g.imports) {
+		if alias := g.imports[path]; alias != path[strings.LastIndex(path, "/")+1:] {
+			fmt.Fprintf(&b, "%s %q\n", alias, path)
+		} else {
+			fmt.Fprintf(&b, "%q\n", path)
+		}
+	}
+	b.WriteString(")\n\n")
+
+	fmt.Fprintf(&b, "// %s builds the application dependencies.\n", g.opts.Type)
+	fmt.Fprintf(&b, "type %s struct {\nmu %s.Mutex\ncleanups []func()\n%s}\n\n", g.opts.Type, sync, g.fields.String())
+
+	fmt.Fprintf(&b, "// New%s returns a %s that builds the instances on demand.\n", g.opts.Type, g.opts.Type)
+	fmt.Fprintf(&b, "func New%s() *%s {\nreturn &%s{}\n}\n\n", g.opts.Type, g.opts.Type, g.opts.Type)
+
+	b.Write(g.methods.Bytes())
+
+	b.WriteString("// Close runs the cleanups of the built instances in the reverse order they were\n// built. Singletons are built again when requested after Close.\n")
+	fmt.Fprintf(&b, "func (c *%s) Close() {\nc.mu.Lock()\ndefer c.mu.Unlock()\n", g.opts.Type)
+	b.WriteString("for i := len(c.cleanups) - 1; i >= 0; i-- {\nc.cleanups[i]()\n}\nc.cleanups = nil\n")
+	b.Write(g.resets.Bytes())
+	b.WriteString("}\n")
+
+	return b.Bytes()
+}
+
+// typeName returns an exported identifier for t, like "DBConfig" for *config.DBConfig.
+func typeName(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Name() == "" {
+		return "Value"
+	}
+	return exported(t.Name())
+}
+
+// exported turns name into an exported identifier, like "ReadReplica" for "read-replica".
+func exported(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	if sb.Len() == 0 || !unicode.IsLetter([]rune(sb.String())[0]) {
+		return "X" + sb.String()
+	}
+	return sb.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
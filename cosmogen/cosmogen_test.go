@@ -0,0 +1,153 @@
+package cosmogen
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gustavosvalentim/cosmo"
+)
+
+func newContainer() *cosmo.Container {
+	c := cosmo.New()
+	c.Add(os.Hostname)
+	c.AddSingleton(strings.NewReader)
+	c.AddNamed("replica", cosmo.NewStats)
+	c.AddToGroup("readers", strings.NewReader)
+	c.Decorate(strings.ToUpper)
+	return c
+}
+
+func TestGenerate(t *testing.T) {
+	var out bytes.Buffer
+	if err := Generate(&out, newContainer(), Options{Package: "wiring"}); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	src := out.String()
+	for _, expected := range []string{
+		"package wiring",
+		"func (c *Container) Reader() (*strings.Reader, error)",
+		"func (c *Container) Replica() (*cosmo.Stats, error)",
+		"func (c *Container) Readers() ([]*strings.Reader, error)",
+		"v, err = os.Hostname()",
+		"v = strings.ToUpper(v)",
+		"c.reader, c.readerDone = v, true",
+	} {
+		if !strings.Contains(src, expected) {
+			t.Errorf("generated code does not contain %q:\n%s", expected, src)
+		}
+	}
+	if strings.Contains(src, "reflect") {
+		t.Error("generated code uses reflection")
+	}
+}
+
+func TestGenerateCompiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a module with the go command")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	root, err := filepath.Abs("..")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var out bytes.Buffer
+	if err := Generate(&out, newContainer(), Options{Package: "wiring"}); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.25.4\n\n" +
+			"require github.com/gustavosvalentim/cosmo v0.0.0\n\n" +
+			"replace github.com/gustavosvalentim/cosmo => " + root + "\n",
+		"wiring/wiring_gen.go": out.String(),
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err.Error())
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	for _, args := range [][]string{{"build", "./..."}, {"vet", "./..."}} {
+		cmd := exec.Command(goBin, args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("go %s failed: %v\n%s\n%s", args[0], err, output, out.String())
+		}
+	}
+}
+
+type Conn struct{}
+
+func NewConn() (*Conn, func()) {
+	return &Conn{}, func() {}
+}
+
+func TestGenerateTransientCleanup(t *testing.T) {
+	c := cosmo.New()
+	c.Add(os.Hostname)
+	c.Add(NewConn)
+	c.AddToGroup("readers", strings.NewReader)
+
+	var out bytes.Buffer
+	if err := Generate(&out, c, Options{Package: "wiring"}); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	src := out.String()
+	if !strings.Contains(src, "v, _ = cosmogen.NewConn()") {
+		t.Errorf("cleanup of the transient provider was not discarded:\n%s", src)
+	}
+	if strings.Contains(src, "c.cleanups = append") {
+		t.Errorf("cleanups of transient providers are recorded:\n%s", src)
+	}
+
+	singleton := cosmo.New()
+	singleton.AddSingleton(NewConn)
+	out.Reset()
+	if err := Generate(&out, singleton, Options{Package: "wiring"}); err != nil {
+		t.Fatal(err.Error())
+	}
+	if !strings.Contains(out.String(), "c.cleanups = append(c.cleanups, cleanup)") {
+		t.Errorf("cleanup of the singleton provider is not recorded:\n%s", out.String())
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	missing := cosmo.New()
+	missing.Add(strings.NewReader)
+
+	anonymous := cosmo.New()
+	anonymous.Add(func() string { return "" })
+
+	cycle := cosmo.New()
+	cycle.Add(strings.ToUpper)
+
+	for name, c := range map[string]*cosmo.Container{
+		"missing":   missing,
+		"anonymous": anonymous,
+		"cycle":     cycle,
+	} {
+		if err := Generate(&bytes.Buffer{}, c, Options{Package: "wiring"}); err == nil {
+			t.Errorf("%s: generate did not return error", name)
+		}
+	}
+
+	if err := Generate(&bytes.Buffer{}, cosmo.New(), Options{}); err == nil {
+		t.Error("generate accepted options without package")
+	}
+}
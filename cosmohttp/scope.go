@@ -0,0 +1,67 @@
+package cosmohttp
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gustavosvalentim/cosmo"
+)
+
+// ErrNoContainer is returned by FromRequest when the request was not served
+// through Middleware.
+var ErrNoContainer = errors.New("no container in request context")
+
+// Middleware gives each request its own child of c, taken from a Container.ChildPool,
+// and disposes it once the handler returns. The child provides the *http.Request
+// and is stored in the request context under cosmo.ContextKey, so handlers resolve
+// request-scoped services with FromRequest. The setup functions register the
+// request-scoped providers; if one of them fails the error is logged and the
+// request is answered with a 500 status. Children are reused by later requests, so
+// they must not be used, or retained by the instances they built, after the handler
+// returns.
+//
+//	mux.Handle("/orders", cosmohttp.Middleware(c, func(req *cosmo.Container, r *http.Request) error {
+//		return req.AddSingleton(NewCurrentUser)
+//	})(ordersHandler))
+func Middleware(c *cosmo.Container, setup ...func(*cosmo.Container, *http.Request) error) func(http.Handler) http.Handler {
+	pool := c.ChildPool()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			child := pool.Get()
+			defer pool.Put(child)
+
+			r = r.WithContext(context.WithValue(r.Context(), cosmo.ContextKey, child))
+			child.AddSingleton(func() *http.Request { return r })
+
+			for _, fn := range setup {
+				if err := fn(child, r); err != nil {
+					log.Printf("cosmohttp: setting up the container of %s %s: %v", r.Method, r.URL.Path, err)
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// FromRequest resolves T from the container of the request, stored by Middleware.
+// Arguments of type context.Context of the constructors receive the request context.
+//
+//	user, err := cosmohttp.FromRequest[*CurrentUser](r)
+func FromRequest[T any](r *http.Request) (T, error) {
+	var v T
+
+	c, ok := r.Context().Value(cosmo.ContextKey).(*cosmo.Container)
+	if !ok {
+		return v, ErrNoContainer
+	}
+
+	err := c.InvokeCtx(r.Context(), func(resolved T) {
+		v = resolved
+	})
+	return v, err
+}
@@ -0,0 +1,90 @@
+package cosmohttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gustavosvalentim/cosmo"
+)
+
+type currentUser struct {
+	Name string
+}
+
+func TestMiddleware(t *testing.T) {
+	c := cosmo.New()
+	disposed := 0
+
+	handler := Middleware(c, func(req *cosmo.Container, r *http.Request) error {
+		return req.AddSingleton(func(r *http.Request) (*currentUser, func()) {
+			return &currentUser{Name: r.Header.Get("X-User")}, func() { disposed++ }
+		})
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, err := FromRequest[*currentUser](r)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		again, _ := FromRequest[*currentUser](r)
+		if again != user {
+			t.Error("request singleton was built twice")
+		}
+		w.Write([]byte(user.Name))
+	}))
+
+	for _, name := range []string{"alice", "bob"} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-User", name)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Body.String() != name {
+			t.Errorf("expected %q, got %q", name, w.Body.String())
+		}
+	}
+
+	if disposed != 2 {
+		t.Errorf("request containers were disposed %d times", disposed)
+	}
+
+	if _, err := FromRequest[*currentUser](httptest.NewRequest(http.MethodGet, "/", nil)); !errors.Is(err, ErrNoContainer) {
+		t.Errorf("expected ErrNoContainer, got %v", err)
+	}
+}
+
+func TestMiddlewareConfiguration(t *testing.T) {
+	c := cosmo.New()
+	c.Configure("greeting", func() string { return "hello" })
+
+	handler := Middleware(c)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		greeting, _ := cosmo.Context(r.Context(), "greeting").(string)
+		w.Write([]byte(greeting))
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Body.String() != "hello" {
+		t.Errorf("configuration of the app container was not resolved, got %q", w.Body.String())
+	}
+}
+
+func BenchmarkMiddleware(b *testing.B) {
+	c := cosmo.New()
+	handler := Middleware(c, func(req *cosmo.Container, r *http.Request) error {
+		return req.AddSingleton(func(r *http.Request) *currentUser {
+			return &currentUser{Name: r.Header.Get("X-User")}
+		})
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := FromRequest[*currentUser](r); err != nil {
+			b.Fatal(err.Error())
+		}
+	}))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		handler.ServeHTTP(w, r)
+	}
+}
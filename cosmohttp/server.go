@@ -0,0 +1,135 @@
+// Package cosmohttp integrates net/http with a cosmo.Container.
+package cosmohttp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gustavosvalentim/cosmo"
+)
+
+// DefaultShutdownTimeout is used when ServerConfig.ShutdownTimeout is zero.
+const DefaultShutdownTimeout = 10 * time.Second
+
+// ServerConfig configures the Server.
+type ServerConfig struct {
+	Addr              string
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// ShutdownTimeout is how long the server waits for active connections when stopping.
+	ShutdownTimeout time.Duration
+}
+
+// Server owns an http.Server, running it until its context is done and shutting
+// it down gracefully.
+type Server struct {
+	server          *http.Server
+	shutdownTimeout time.Duration
+	ready           chan struct{}
+	once            sync.Once
+	mu              sync.Mutex
+	addr            net.Addr
+}
+
+// NewServer creates a Server that serves handler with cfg.
+func NewServer(handler http.Handler, cfg ServerConfig) *Server {
+	shutdownTimeout := cfg.ShutdownTimeout
+	if shutdownTimeout == 0 {
+		shutdownTimeout = DefaultShutdownTimeout
+	}
+
+	return &Server{
+		server: &http.Server{
+			Addr:              cfg.Addr,
+			Handler:           handler,
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+			ReadTimeout:       cfg.ReadTimeout,
+			WriteTimeout:      cfg.WriteTimeout,
+			IdleTimeout:       cfg.IdleTimeout,
+		},
+		shutdownTimeout: shutdownTimeout,
+		ready:           make(chan struct{}),
+	}
+}
+
+// Provide registers a singleton *Server built from the http.Handler and the
+// ServerConfig registered in the container. The server is shut down when the
+// container is closed.
+//
+//	c.AddSingleton(func() cosmohttp.ServerConfig { return cosmohttp.ServerConfig{Addr: ":8080"} })
+//	c.AddSingleton(NewRouter) // returns http.Handler
+//	cosmohttp.Provide(c)
+//	defer c.Close()
+//
+//	err := c.InvokeCtx(ctx, func(ctx context.Context, s *cosmohttp.Server) error {
+//		return s.Run(ctx)
+//	})
+func Provide(c *cosmo.Container) error {
+	return c.AddSingleton(func(handler http.Handler, cfg ServerConfig) (*Server, func()) {
+		s := NewServer(handler, cfg)
+		return s, func() { s.Shutdown(context.Background()) }
+	})
+}
+
+// Run listens on the configured address and serves requests until ctx is done,
+// then shuts the server down, waiting at most ShutdownTimeout for active
+// connections. It returns nil when the server was stopped by the context.
+func (s *Server) Run(ctx context.Context) error {
+	addr := s.server.Addr
+	if addr == "" {
+		addr = ":http"
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.addr = ln.Addr()
+	s.mu.Unlock()
+	s.once.Do(func() { close(s.ready) })
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- s.server.Serve(ln)
+	}()
+
+	select {
+	case err := <-errs:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	return s.Shutdown(context.Background())
+}
+
+// Shutdown gracefully stops the server, waiting until ctx is done or at most
+// ShutdownTimeout for the active connections.
+func (s *Server) Shutdown(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.shutdownTimeout)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
+
+// Ready returns a channel that's closed once the server is listening.
+func (s *Server) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Addr returns the address the server is listening on, or nil if it's not
+// listening yet.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addr
+}
@@ -0,0 +1,62 @@
+package cosmohttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gustavosvalentim/cosmo"
+)
+
+func TestServer(t *testing.T) {
+	c := cosmo.New()
+	c.AddSingleton(func() ServerConfig {
+		return ServerConfig{Addr: "127.0.0.1:0", ShutdownTimeout: time.Second}
+	})
+	c.AddSingleton(func() http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, "ok")
+		})
+	})
+	if err := Provide(c); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		errs <- c.InvokeCtx(ctx, func(ctx context.Context, s *Server) error {
+			return s.Run(ctx)
+		})
+	}()
+
+	var server *Server
+	c.Invoke(func(s *Server) { server = s })
+
+	select {
+	case <-server.Ready():
+	case err := <-errs:
+		t.Fatalf("server stopped before being ready: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server was not ready in time")
+	}
+
+	res, err := http.Get("http://" + server.Addr().String())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	if string(body) != "ok" {
+		t.Errorf("unexpected response %q", body)
+	}
+
+	cancel()
+	if err := <-errs; err != nil {
+		t.Error(err.Error())
+	}
+
+	c.Close()
+}
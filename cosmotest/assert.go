@@ -0,0 +1,97 @@
+package cosmotest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gustavosvalentim/cosmo"
+)
+
+// AssertResolved fails the test if T wasn't resolved through the container.
+//
+//	handler.ServeHTTP(rec, req)
+//	cosmotest.AssertResolved[Mailer](t, tc)
+func AssertResolved[T any](tb testing.TB, tc *Container) {
+	tb.Helper()
+	typ := reflect.TypeFor[T]()
+
+	tc.mu.Lock()
+	resolved := tc.resolved[typ]
+	tc.mu.Unlock()
+
+	if !resolved {
+		tb.Errorf("%v was not resolved", typ)
+	}
+}
+
+// AssertNotResolved fails the test if T was resolved through the container.
+func AssertNotResolved[T any](tb testing.TB, tc *Container) {
+	tb.Helper()
+	typ := reflect.TypeFor[T]()
+
+	tc.mu.Lock()
+	resolved := tc.resolved[typ]
+	tc.mu.Unlock()
+
+	if resolved {
+		tb.Errorf("%v was resolved", typ)
+	}
+}
+
+// AssertSingletonReused fails the test unless T is provided as a singleton and
+// every resolution of T returned the same instance. T must have been resolved at
+// least twice, so wiring mistakes like a singleton rebuilt by a child container or
+// replaced by a transient provider are caught.
+func AssertSingletonReused[T any](tb testing.TB, tc *Container) {
+	tb.Helper()
+	typ := reflect.TypeFor[T]()
+
+	if provider, ok := tc.Provider(typ); ok && provider.Scope != cosmo.ScopeSingleton {
+		tb.Errorf("%v is provided with %v", typ, provider.Scope)
+		return
+	}
+
+	tc.mu.Lock()
+	r, ok := tc.resolutions[typ]
+	var count int
+	var reused bool
+	if ok {
+		count, reused = r.count, r.reused
+	}
+	tc.mu.Unlock()
+
+	switch {
+	case count < 2:
+		tb.Errorf("%v was resolved %d times, expected at least 2", typ, count)
+	case !reused:
+		tb.Errorf("%v resolved different instances", typ)
+	}
+}
+
+// sameInstance reports whether a and b hold the same instance: the same pointer,
+// map, channel or function, or equal values for other comparable types.
+func sameInstance(a, b reflect.Value) bool {
+	if a.Kind() == reflect.Interface {
+		a = a.Elem()
+	}
+	if b.Kind() == reflect.Interface {
+		b = b.Elem()
+	}
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	switch a.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return a.Pointer() == b.Pointer()
+	case reflect.Slice:
+		return a.Pointer() == b.Pointer() && a.Len() == b.Len()
+	}
+	if a.Comparable() {
+		return a.Equal(b)
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package cosmotest
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestAssertions(t *testing.T) {
+	tc := New(t).
+		ProvideSingleton(NewConfig).
+		Provide(NewSMTPMailer)
+
+	mailer := &FakeMailer{}
+	OverrideWithMock[Mailer](t, tc, mailer)
+
+	for i := 0; i < 2; i++ {
+		err := tc.Invoke(func(m Mailer, cfg Config) {
+			if m != mailer {
+				t.Error("mock was not injected")
+			}
+		})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	AssertResolved[Mailer](t, tc)
+	AssertResolved[Config](t, tc)
+	AssertNotResolved[*SMTPMailer](t, tc)
+	AssertSingletonReused[Config](t, tc)
+
+	if resolved := tc.Resolved(); len(resolved) != 2 {
+		t.Errorf("wrong resolved types %v", resolved)
+	}
+}
+
+func TestAssertionsFail(t *testing.T) {
+	tc := New(t).
+		Provide(func() *FakeMailer { return &FakeMailer{} }).
+		ProvideSingleton(NewConfig)
+	tc.Invoke(func(*FakeMailer, *FakeMailer) {})
+
+	var rec recorder
+	AssertResolved[Mailer](&rec, tc)
+	AssertNotResolved[*FakeMailer](&rec, tc)
+	AssertSingletonReused[*FakeMailer](&rec, tc)
+	AssertSingletonReused[Config](&rec, tc)
+
+	if len(rec.errors) != 4 {
+		t.Errorf("expected 4 failed assertions, got %v", rec.errors)
+	}
+}
+
+func TestNewFailsOnRegistrationErrors(t *testing.T) {
+	var rec recorder
+	done := make(chan struct{})
+	// Fatal stops the goroutine, like it stops the test.
+	go func() {
+		defer close(done)
+		New(&rec).Provide("not a constructor")
+	}()
+	<-done
+
+	if !rec.fatal {
+		t.Error("invalid registration did not fail the test")
+	}
+}
+
+// recorder is a testing.TB recording the failures instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+	fatal  bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Cleanup(func()) {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, format)
+}
+
+func (r *recorder) Fatal(args ...any) {
+	r.fatal = true
+	runtime.Goexit()
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Fatal()
+}
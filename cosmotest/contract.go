@@ -0,0 +1,62 @@
+package cosmotest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gustavosvalentim/cosmo"
+)
+
+// Contract runs suite as a subtest for every provider of T registered in c: the
+// provider of the type, the named providers, the group members and the conditional
+// providers, whether their profiles are active or not. Each subtest receives a new
+// instance, so fakes are held to the same contract as the real implementations.
+//
+//	cosmotest.Contract[Mailer](t, c, func(t *testing.T, m Mailer) {
+//		if err := m.Send("user@example.com"); err != nil {
+//			t.Error(err)
+//		}
+//	})
+func Contract[T any](t *testing.T, c *cosmo.Container, suite func(t *testing.T, impl T)) {
+	t.Helper()
+
+	typ := reflect.TypeFor[T]()
+	found := false
+
+	for _, info := range c.Providers() {
+		if info.Type != typ {
+			continue
+		}
+		found = true
+
+		t.Run(contractName(info), func(t *testing.T) {
+			v, err := c.Construct(info)
+			if err != nil {
+				t.Fatalf("building %s: %v", info.Constructor, err)
+			}
+			impl, _ := v.(T)
+			suite(t, impl)
+		})
+	}
+
+	if !found {
+		t.Errorf("no provider of %v registered", typ)
+	}
+}
+
+// contractName names the subtest of a provider after how it was registered, like
+// "name=fake/cosmotest.NewFakeMailer".
+func contractName(info cosmo.ProviderInfo) string {
+	name := funcName(info.Value)
+	name = name[strings.LastIndex(name, "/")+1:]
+	switch {
+	case info.Name != "":
+		return "name=" + info.Name + "/" + name
+	case info.Group != "":
+		return "group=" + info.Group + "/" + name
+	case len(info.Profiles) > 0:
+		return "profile=" + strings.Join(info.Profiles, ",") + "/" + name
+	}
+	return name
+}
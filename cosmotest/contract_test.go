@@ -0,0 +1,33 @@
+package cosmotest
+
+import (
+	"testing"
+
+	"github.com/gustavosvalentim/cosmo"
+)
+
+func TestContract(t *testing.T) {
+	c := cosmo.New()
+	c.Add(NewConfig)
+	c.AddSingleton(NewSMTPMailer)
+	c.AddNamed("fake", func() Mailer { return &FakeMailer{} })
+	c.AddWhen(cosmo.Profile("canary"), func() Mailer { return &FakeMailer{} })
+
+	var names []string
+	Contract[Mailer](t, c, func(t *testing.T, m Mailer) {
+		names = append(names, t.Name())
+		if err := m.Send("user@example.com"); err != nil {
+			t.Error(err.Error())
+		}
+	})
+
+	if len(names) != 3 {
+		t.Errorf("expected the contract to run for 3 providers, ran for %v", names)
+	}
+
+	c.Invoke(func(m Mailer) {
+		if _, ok := m.(*SMTPMailer); !ok {
+			t.Error("contract changed the provider of the type")
+		}
+	})
+}
@@ -0,0 +1,291 @@
+// Package cosmotest provides helpers to use a cosmo.Container in tests.
+package cosmotest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gustavosvalentim/cosmo"
+)
+
+var (
+	errorType   = reflect.TypeFor[error]()
+	cleanupType = reflect.TypeFor[func()]()
+)
+
+// Container wraps a cosmo.Container, keeping track of the providers overridden
+// by the test and of the types resolved while the test runs.
+type Container struct {
+	*cosmo.Container
+	tb            testing.TB
+	substitutions []Substitution
+	overridden    map[reflect.Type]int
+	mu            sync.Mutex
+	used          map[reflect.Type]bool
+	resolved      map[reflect.Type]bool
+	order         []reflect.Type
+	resolutions   map[reflect.Type]*resolution
+}
+
+// resolution holds how a type was resolved during the test.
+type resolution struct {
+	first  reflect.Value
+	count  int
+	reused bool
+}
+
+// New returns a test container around a new cosmo.Container, which is closed when
+// the test finishes. Registrations made with Provide fail the test when they
+// return an error, so test setups don't need to check every one.
+//
+//	tc := cosmotest.New(t).
+//		ProvideSingleton(NewConfig).
+//		Provide(NewUserService)
+func New(tb testing.TB) *Container {
+	tc := Wrap(cosmo.New())
+	tc.tb = tb
+	tb.Cleanup(func() {
+		if err := tc.Close(); err != nil {
+			tb.Errorf("closing the container: %v", err)
+		}
+	})
+	return tc
+}
+
+// Wrap returns a test container around c. Usually c is the container built by the
+// application composition root, which is then partially overridden with fakes. Wrap
+// records the resolutions through a resolve middleware, so it panics if c is sealed.
+func Wrap(c *cosmo.Container) *Container {
+	tc := &Container{
+		Container:   c,
+		overridden:  make(map[reflect.Type]int),
+		used:        make(map[reflect.Type]bool),
+		resolved:    make(map[reflect.Type]bool),
+		resolutions: make(map[reflect.Type]*resolution),
+	}
+	if err := c.UseResolveMiddleware(tc.record); err != nil {
+		panic(err)
+	}
+	return tc
+}
+
+// record is a resolve middleware that stores every type resolved through the container.
+func (tc *Container) record(next cosmo.ResolveFunc) cosmo.ResolveFunc {
+	return func(ctx context.Context, t reflect.Type) (reflect.Value, error) {
+		v, err := next(ctx, t)
+		if err != nil {
+			return v, err
+		}
+
+		tc.mu.Lock()
+		defer tc.mu.Unlock()
+		if _, ok := tc.overridden[t]; ok {
+			tc.used[t] = true
+		}
+		if !tc.resolved[t] {
+			tc.resolved[t] = true
+			tc.order = append(tc.order, t)
+			tc.resolutions[t] = &resolution{first: v, reused: true}
+		}
+		r := tc.resolutions[t]
+		r.count++
+		r.reused = r.reused && sameInstance(r.first, v)
+		return v, err
+	}
+}
+
+// Override replaces the provider of the type built by constructor, keeping the scope of
+// the replaced provider. If the type was not registered, the constructor is added
+// with cosmo.ScopeTransient. Instances of the replaced provider cached by the
+// container are evicted, so the fake is used from then on.
+func (tc *Container) Override(constructor any) error {
+	fake := reflect.ValueOf(constructor)
+	if fake.Kind() != reflect.Func || fake.Type().NumOut() == 0 {
+		return fmt.Errorf("override expects a constructor, got %T", constructor)
+	}
+
+	t := fake.Type().Out(0)
+	scope := cosmo.ScopeTransient
+	substitution := Substitution{
+		Type: t,
+		Fake: funcName(fake),
+	}
+
+	if replaced, ok := tc.Provider(t); ok {
+		scope = replaced.Scope
+		substitution.Real = funcName(replaced.Value)
+	}
+	if scope == cosmo.ScopeExpiring {
+		// Fakes don't expire.
+		scope = cosmo.ScopeSingleton
+	}
+
+	if err := tc.AddWithScope(scope, constructor); err != nil {
+		return err
+	}
+	for _, product := range products(fake.Type()) {
+		if err := tc.EvictInstance(product); err != nil {
+			return err
+		}
+	}
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	delete(tc.used, t)
+	if i, ok := tc.overridden[t]; ok {
+		tc.substitutions[i].Fake = substitution.Fake
+		return nil
+	}
+
+	tc.overridden[t] = len(tc.substitutions)
+	tc.substitutions = append(tc.substitutions, substitution)
+
+	return nil
+}
+
+// products returns the types built by the constructor type fn: the values it returns
+// before the error and the cleanup function.
+func products(fn reflect.Type) []reflect.Type {
+	var types []reflect.Type
+	for i := range fn.NumOut() {
+		out := fn.Out(i)
+		if out == errorType || out == cleanupType {
+			break
+		}
+		types = append(types, out)
+	}
+	return types
+}
+
+// Provide adds the constructor with cosmo.ScopeTransient, failing the test if it
+// returns an error. It panics on containers created with Wrap.
+func (tc *Container) Provide(constructor any) *Container {
+	return tc.ProvideWithScope(cosmo.ScopeTransient, constructor)
+}
+
+// ProvideSingleton adds the constructor with cosmo.ScopeSingleton, failing the test
+// if it returns an error.
+func (tc *Container) ProvideSingleton(constructor any) *Container {
+	return tc.ProvideWithScope(cosmo.ScopeSingleton, constructor)
+}
+
+// ProvideWithScope adds the constructor with the scope, failing the test if it
+// returns an error.
+func (tc *Container) ProvideWithScope(scope cosmo.Scope, constructor any) *Container {
+	if err := tc.AddWithScope(scope, constructor); err != nil {
+		tc.fatal(fmt.Errorf("providing %T: %w", constructor, err))
+	}
+	return tc
+}
+
+// fatal fails the test of a container created with New, or panics.
+func (tc *Container) fatal(err error) {
+	if tc.tb == nil {
+		panic(err)
+	}
+	tc.tb.Helper()
+	tc.tb.Fatal(err)
+}
+
+// OverrideWithMock overrides the provider of T, like Container.Override, with a
+// provider returning mock, failing the test if it can't be overridden.
+//
+//	mailer := &FakeMailer{}
+//	cosmotest.OverrideWithMock[Mailer](t, tc, mailer)
+func OverrideWithMock[T any](tb testing.TB, tc *Container, mock T) {
+	tb.Helper()
+	if err := tc.Override(func() T { return mock }); err != nil {
+		tb.Fatalf("overriding %v: %v", reflect.TypeFor[T](), err)
+	}
+}
+
+// Resolved returns the types resolved so far, in the order they were first resolved.
+func (tc *Container) Resolved() []reflect.Type {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return append([]reflect.Type(nil), tc.order...)
+}
+
+// Substitution describes a provider replaced by a fake.
+type Substitution struct {
+	Type reflect.Type
+	// Real is the name of the replaced constructor, it's empty when the type was not registered.
+	Real string
+	Fake string
+	// Used reports whether the fake was resolved during the test, after the override.
+	Used bool
+}
+
+// RealProvider describes a provider that was not overridden and was resolved during the test.
+type RealProvider struct {
+	Type        reflect.Type
+	Constructor string
+}
+
+// SubstitutionReport lists the overridden providers and the real providers
+// still used by the test.
+type SubstitutionReport struct {
+	Substitutions []Substitution
+	Real          []RealProvider
+}
+
+// SubstitutionReport returns the providers replaced by fakes and the real providers
+// resolved so far, in the order they were first resolved. It's useful to catch tests
+// that silently hit real infrastructure.
+func (tc *Container) SubstitutionReport() SubstitutionReport {
+	var report SubstitutionReport
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	for _, s := range tc.substitutions {
+		s.Used = tc.used[s.Type]
+		report.Substitutions = append(report.Substitutions, s)
+	}
+
+	for _, t := range tc.order {
+		if _, ok := tc.overridden[t]; ok {
+			continue
+		}
+		provider, ok := tc.Provider(t)
+		if !ok {
+			continue
+		}
+		report.Real = append(report.Real, RealProvider{
+			Type:        t,
+			Constructor: funcName(provider.Value),
+		})
+	}
+
+	return report
+}
+
+// String formats the report, one provider per line.
+func (r SubstitutionReport) String() string {
+	var sb strings.Builder
+	for _, s := range r.Substitutions {
+		replaced := s.Real
+		if replaced == "" {
+			replaced = "<none>"
+		}
+		fmt.Fprintf(&sb, "substituted %v: %s -> %s (used: %t)\n", s.Type, replaced, s.Fake, s.Used)
+	}
+	for _, p := range r.Real {
+		fmt.Fprintf(&sb, "real %v: %s\n", p.Type, p.Constructor)
+	}
+	return sb.String()
+}
+
+// funcName returns the name of the function held by v.
+func funcName(v reflect.Value) string {
+	fn := runtime.FuncForPC(v.Pointer())
+	if fn == nil {
+		return v.Type().String()
+	}
+	return fn.Name()
+}
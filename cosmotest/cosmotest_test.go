@@ -0,0 +1,110 @@
+package cosmotest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gustavosvalentim/cosmo"
+)
+
+type Config struct {
+	URL string
+}
+
+type Mailer interface {
+	Send(to string) error
+}
+
+type SMTPMailer struct {
+	Config Config
+}
+
+func (m *SMTPMailer) Send(to string) error {
+	return nil
+}
+
+type FakeMailer struct {
+	Sent []string
+}
+
+func (m *FakeMailer) Send(to string) error {
+	m.Sent = append(m.Sent, to)
+	return nil
+}
+
+func NewConfig() Config {
+	return Config{URL: "smtp://localhost"}
+}
+
+func NewSMTPMailer(cfg Config) Mailer {
+	return &SMTPMailer{Config: cfg}
+}
+
+func NewFakeMailer() Mailer {
+	return &FakeMailer{}
+}
+
+func TestSubstitutionReport(t *testing.T) {
+	c := cosmo.New()
+	c.AddSingleton(NewConfig)
+	c.AddSingleton(NewSMTPMailer)
+
+	tc := Wrap(c)
+	if err := tc.Override(NewFakeMailer); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err := tc.Invoke(func(m Mailer, cfg Config) {
+		if _, ok := m.(*FakeMailer); !ok {
+			t.Error("override was not injected")
+		}
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	report := tc.SubstitutionReport()
+	if len(report.Substitutions) != 1 {
+		t.Fatalf("expected 1 substitution, got %d", len(report.Substitutions))
+	}
+
+	s := report.Substitutions[0]
+	if !strings.HasSuffix(s.Real, "NewSMTPMailer") || !strings.HasSuffix(s.Fake, "NewFakeMailer") || !s.Used {
+		t.Errorf("wrong substitution %+v", s)
+	}
+
+	if len(report.Real) != 1 || !strings.HasSuffix(report.Real[0].Constructor, "NewConfig") {
+		t.Errorf("wrong real providers %+v", report.Real)
+	}
+
+	if !strings.Contains(report.String(), "NewFakeMailer") {
+		t.Error("report string does not contain the fake")
+	}
+}
+
+func TestOverrideResolvedSingleton(t *testing.T) {
+	c := cosmo.New()
+	c.AddSingleton(NewConfig)
+	c.AddSingleton(NewSMTPMailer)
+
+	tc := Wrap(c)
+	tc.Invoke(func(Mailer) {})
+
+	mock := &FakeMailer{}
+	OverrideWithMock[Mailer](t, tc, mock)
+	if s := tc.SubstitutionReport().Substitutions[0]; s.Used {
+		t.Error("fake was reported as used before being resolved")
+	}
+
+	err := tc.Invoke(func(m Mailer) {
+		if m != mock {
+			t.Errorf("cached %T was resolved instead of the fake", m)
+		}
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	if s := tc.SubstitutionReport().Substitutions[0]; !s.Used {
+		t.Error("fake was not reported as used")
+	}
+}
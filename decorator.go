@@ -0,0 +1,88 @@
+package cosmo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Decorate registers a decorator for the type returned by fn. A decorator receives the
+// instance built by the provider and returns the value that's injected in its place,
+// which allows wrapping a registration without changing it.
+//
+//	c.Decorate(func(inner DBService, log Logger) DBService {
+//		return &loggingDB{inner: inner, log: log}
+//	})
+//
+// The decorator must take the decorated type as one of its arguments, the other
+// arguments are resolved from the container. It must return T or (T, error).
+// Decorators are applied in the order they were registered, so the last one is the
+// outermost, and they apply to every provider of the type, regardless of its scope.
+func (c *Container) Decorate(fn any) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return errors.New("decorator must be a function")
+	}
+
+	t := v.Type()
+	if t.NumOut() == 0 || t.NumOut() > 2 || (t.NumOut() == 2 && t.Out(1) != errorType) {
+		return errors.New("decorator must return T or (T, error)")
+	}
+
+	decorated := t.Out(0)
+	if decoratedIndex(t, decorated) < 0 {
+		return fmt.Errorf("decorator of %v must receive a %v argument", decorated, decorated)
+	}
+
+	c.decorators[decorated] = append(c.decorators[decorated], v)
+	return c.EvictInstance(decorated)
+}
+
+// decoratedIndex returns the position of the decorated argument in the decorator,
+// or -1 if the decorator doesn't receive it.
+func decoratedIndex(fn reflect.Type, decorated reflect.Type) int {
+	for i := 0; i < fn.NumIn(); i++ {
+		if fn.In(i) == decorated {
+			return i
+		}
+	}
+	return -1
+}
+
+// decorate applies the decorators registered for t to the instance.
+func (c *Container) decorate(ctx context.Context, t reflect.Type, instance reflect.Value) (reflect.Value, error) {
+	for _, decorator := range c.decorators[t] {
+		p := planOf(decorator.Type())
+		index := decoratedIndex(decorator.Type(), t)
+		args := make([]reflect.Value, len(p.args))
+
+		for i := range p.args {
+			if i == index {
+				args[i] = instance
+				continue
+			}
+
+			val, err := c.resolveArg(ctx, p, i)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			args[i] = val
+		}
+
+		out, err := c.construct(ctx, t, decorator, args)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if len(out) == 2 && !out[1].IsNil() {
+			return reflect.Value{}, out[1].Interface().(error)
+		}
+
+		instance = out[0]
+	}
+
+	return instance, nil
+}
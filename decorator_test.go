@@ -0,0 +1,81 @@
+package cosmo
+
+import (
+	"reflect"
+	"testing"
+)
+
+type LoggingDBService struct {
+	Inner DBService
+	Name  string
+}
+
+func (svc *LoggingDBService) Get() error {
+	return svc.Inner.Get()
+}
+
+func TestDecorate(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() Config {
+		return Config{
+			URL: DBURL,
+		}
+	})
+	c.AddSingleton(func(cfg Config) DBService {
+		return &SQLDBService{
+			Config: cfg,
+		}
+	})
+
+	decoratorCallTimes := 0
+	err := c.Decorate(func(inner DBService) DBService {
+		decoratorCallTimes++
+		return &LoggingDBService{Inner: inner, Name: "first"}
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	err = c.Decorate(func(cfg Config, inner DBService) (DBService, error) {
+		return &LoggingDBService{Inner: inner, Name: cfg.URL}, nil
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	for i := 0; i < 2; i++ {
+		err = c.Invoke(func(db DBService) {
+			outer, ok := db.(*LoggingDBService)
+			if !ok || outer.Name != DBURL {
+				t.Fatal("last decorator is not the outermost")
+			}
+			inner, ok := outer.Inner.(*LoggingDBService)
+			if !ok || inner.Name != "first" {
+				t.Fatal("first decorator was not applied")
+			}
+			if _, ok := inner.Inner.(*SQLDBService); !ok {
+				t.Error("decorators did not wrap the provider instance")
+			}
+		})
+		if err != nil {
+			t.Error(err.Error())
+		}
+	}
+
+	if decoratorCallTimes != 1 {
+		t.Errorf("decorator of a singleton was called %d times", decoratorCallTimes)
+	}
+}
+
+func TestDecorateInvalid(t *testing.T) {
+	c := New()
+	if err := c.Decorate(func(cfg Config) DBService { return nil }); err == nil {
+		t.Error("decorator without the decorated argument was accepted")
+	}
+	if err := c.Decorate(LoggingDBService{}); err == nil {
+		t.Error("decorator that is not a function was accepted")
+	}
+	if len(c.decorators[reflect.TypeFor[DBService]()]) != 0 {
+		t.Error("invalid decorator was registered")
+	}
+}
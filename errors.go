@@ -0,0 +1,95 @@
+package cosmo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	// ErrNoProvider matches, with errors.Is, the errors returned when a type, name or
+	// group is not registered in the container.
+	ErrNoProvider = errors.New("no provider")
+	// ErrCycle matches, with errors.Is, the errors returned when constructors depend
+	// on each other.
+	ErrCycle = errors.New("dependency cycle")
+	// ErrInvalidConstructor is returned when registering a value that is not a
+	// valid constructor.
+	ErrInvalidConstructor = errors.New("invalid constructor")
+)
+
+// NoProviderError is returned when a type, name or group is not registered.
+type NoProviderError struct {
+	// Type is the type that could not be resolved, it's nil for names and groups.
+	Type reflect.Type
+	// Name is the name of the provider or group that could not be resolved.
+	Name string
+	// Chain holds the types being resolved, the outermost first.
+	Chain []reflect.Type
+	// Profiles holds the inactive profiles the type is registered under.
+	Profiles []string
+}
+
+func (e *NoProviderError) Error() string {
+	var msg string
+	if e.Type != nil {
+		msg = fmt.Sprintf("no provider for type %v", e.Type)
+	} else {
+		msg = fmt.Sprintf("no provider named %q", e.Name)
+	}
+	if len(e.Profiles) > 0 {
+		msg += fmt.Sprintf(", it's only registered under the inactive profiles %s", strings.Join(e.Profiles, ", "))
+	}
+	if len(e.Chain) > 0 {
+		msg += fmt.Sprintf(" (resolving %s)", formatChain(e.Chain))
+	}
+	return msg
+}
+
+// Is reports whether target is ErrNoProvider.
+func (e *NoProviderError) Is(target error) bool {
+	return target == ErrNoProvider
+}
+
+// CycleError is returned when a constructor depends, directly or not, on the type
+// it builds.
+type CycleError struct {
+	// Path holds the types of the cycle, starting and ending with the same type.
+	Path []reflect.Type
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("dependency cycle: %s", formatChain(e.Path))
+}
+
+// Is reports whether target is ErrCycle.
+func (e *CycleError) Is(target error) bool {
+	return target == ErrCycle
+}
+
+// ConstructorError wraps the error returned by a constructor.
+type ConstructorError struct {
+	// Type is the type being built by the constructor that failed.
+	Type reflect.Type
+	// Chain holds the types being resolved, the outermost first.
+	Chain []reflect.Type
+	Err   error
+}
+
+func (e *ConstructorError) Error() string {
+	return fmt.Sprintf("constructor of %v failed: %v (resolving %s)", e.Type, e.Err, formatChain(e.Chain))
+}
+
+func (e *ConstructorError) Unwrap() error {
+	return e.Err
+}
+
+// formatChain formats the types of a resolution chain, like "A -> B -> C".
+func formatChain(chain []reflect.Type) string {
+	types := make([]string, len(chain))
+	for i, t := range chain {
+		types[i] = t.String()
+	}
+	return strings.Join(types, " -> ")
+}
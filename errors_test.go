@@ -0,0 +1,102 @@
+package cosmo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNoProviderError(t *testing.T) {
+	c := New()
+	c.Add(func(cfg Config) DBService {
+		return &SQLDBService{
+			Config: cfg,
+		}
+	})
+
+	err := c.Invoke(func(db DBService) {})
+	if !errors.Is(err, ErrNoProvider) {
+		t.Fatalf("expected ErrNoProvider, got %v", err)
+	}
+
+	var noProvider *NoProviderError
+	if !errors.As(err, &noProvider) {
+		t.Fatal("could not get *NoProviderError")
+	}
+	if noProvider.Type != reflect.TypeFor[Config]() || !reflect.DeepEqual(noProvider.Chain, []reflect.Type{reflect.TypeFor[DBService]()}) {
+		t.Errorf("wrong error %+v", noProvider)
+	}
+
+	var bnd struct {
+		DB DBService `cosmo:"name=primary"`
+	}
+	if err = c.Bind(&bnd); !errors.Is(err, ErrNoProvider) {
+		t.Errorf("expected ErrNoProvider for named provider, got %v", err)
+	}
+}
+
+func TestConstructorError(t *testing.T) {
+	c := New()
+	expected := errors.New("connection refused")
+	c.Add(func() (DBService, error) {
+		return nil, expected
+	})
+
+	err := c.Invoke(func(db DBService) {})
+
+	var ctorErr *ConstructorError
+	if !errors.As(err, &ctorErr) || ctorErr.Type != reflect.TypeFor[DBService]() {
+		t.Fatalf("expected *ConstructorError, got %v", err)
+	}
+	if !errors.Is(err, expected) || errors.Is(err, ErrNoProvider) {
+		t.Error("constructor error was not wrapped")
+	}
+}
+
+func TestCycleError(t *testing.T) {
+	c := New()
+	c.Add(func(db DBService) Config {
+		return Config{}
+	})
+	c.Add(func(cfg Config) DBService {
+		return &SQLDBService{
+			Config: cfg,
+		}
+	})
+
+	err := c.Invoke(func(db DBService) {})
+
+	var cycle *CycleError
+	if !errors.As(err, &cycle) || !errors.Is(err, ErrCycle) {
+		t.Fatalf("expected *CycleError, got %v", err)
+	}
+
+	dbType, cfgType := reflect.TypeFor[DBService](), reflect.TypeFor[Config]()
+	if !reflect.DeepEqual(cycle.Path, []reflect.Type{dbType, cfgType, dbType}) {
+		t.Errorf("wrong cycle path %v", cycle.Path)
+	}
+}
+
+func TestNamedProviderDependingOnItsType(t *testing.T) {
+	c := New()
+	c.Add(func() DBService {
+		return &SQLDBService{}
+	})
+	c.AddNamed("logging", func(inner DBService) DBService {
+		return &LoggingDBService{Inner: inner}
+	})
+
+	var bnd struct {
+		DB DBService `cosmo:"name=logging"`
+	}
+	if err := c.Bind(&bnd); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestInvalidConstructorError(t *testing.T) {
+	c := New()
+	if err := c.Add(Config{}); !errors.Is(err, ErrInvalidConstructor) {
+		t.Errorf("expected ErrInvalidConstructor, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package cosmo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ExpiryPolicy defines when the instance of a provider added with AddExpiring
+// expires. At least one of the limits must be set.
+type ExpiryPolicy struct {
+	// TTL is how long an instance is served after being built. Zero means no limit.
+	TTL time.Duration
+	// MaxUses is how many resolutions an instance serves. Zero means no limit.
+	MaxUses int
+}
+
+// expiringInstance is the cached instance of a provider added with AddExpiring.
+type expiringInstance struct {
+	mu      sync.Mutex
+	value   reflect.Value
+	built   bool
+	expires time.Time
+	uses    int
+}
+
+// valid reports whether the instance can still be served.
+func (i *expiringInstance) valid(policy ExpiryPolicy, now time.Time) bool {
+	if !i.built {
+		return false
+	}
+	if policy.TTL > 0 && !now.Before(i.expires) {
+		return false
+	}
+	return policy.MaxUses <= 0 || i.uses < policy.MaxUses
+}
+
+// AddExpiring adds the constructor with ScopeExpiring: its instance is cached like a
+// singleton, but once it expires, according to the policy, the next resolution
+// disposes it, running its cleanup function, and builds a new one. It suits
+// short-lived values, like auth tokens, and clients refreshed periodically:
+//
+//	c.AddExpiring(cosmo.ExpiryPolicy{TTL: 5 * time.Minute}, func(auth *AuthClient) (Token, error) {
+//		return auth.Token()
+//	})
+//
+// Since expired instances are disposed, consumers should resolve them each time they
+// use them, with Container.Invoke for instance, instead of keeping them.
+func (c *Container) AddExpiring(policy ExpiryPolicy, constructor any) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	if policy.TTL <= 0 && policy.MaxUses <= 0 {
+		return errors.New("expiry policy must set a TTL or a maximum number of uses")
+	}
+	_, v, err := spec(constructor)
+	if err != nil {
+		return err
+	}
+
+	for _, provider := range c.specs(ScopeExpiring, v) {
+		provider.expiry = &policy
+		c.providers[provider.Type] = provider
+		runCleanups(c.instances().evict(map[reflect.Type]bool{provider.Type: true}))
+	}
+	return nil
+}
+
+// checkScope returns an error for scopes that can't be set when adding a provider.
+func checkScope(scope Scope) error {
+	if scope == ScopeExpiring {
+		return errors.New("ScopeExpiring needs an expiry policy, use AddExpiring")
+	}
+	return nil
+}
+
+// resolveExpiring returns the cached instance of the provider, or builds a new one
+// if it expired, disposing the expired one.
+func (c *Container) resolveExpiring(ctx context.Context, provider Spec) (reflect.Value, error) {
+	// The cycles are detected before waiting for the instance, which would deadlock.
+	if _, err := withChain(ctx, provider); err != nil {
+		return reflect.Value{}, err
+	}
+
+	cache := c.cacheOf(ctx)
+	inst := cache.expiringInstance(provider.Type)
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	now := time.Now()
+	if inst.valid(*provider.expiry, now) {
+		inst.uses++
+		c.metrics.CacheHit(provider.Type)
+		return inst.value, nil
+	}
+
+	if inst.built {
+		inst.built = false
+		// The cleanup functions of providers added by type never fail.
+		runCleanups(cache.takeExpired(provider.Type))
+	}
+
+	v, err := c.call(ctx, provider)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	inst.value = v
+	inst.built = true
+	inst.uses = 1
+	if provider.expiry.TTL > 0 {
+		inst.expires = now.Add(provider.expiry.TTL)
+	}
+	return v, nil
+}
@@ -0,0 +1,107 @@
+package cosmo
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type Token struct {
+	Value int
+}
+
+func TestExpiringTTL(t *testing.T) {
+	c := New()
+	built, disposed := 0, 0
+	err := c.AddExpiring(ExpiryPolicy{TTL: 50 * time.Millisecond}, func() (Token, func()) {
+		built++
+		return Token{Value: built}, func() { disposed++ }
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	c.Invoke(func(Token) {})
+	c.Invoke(func(tok Token) {
+		if tok.Value != 1 {
+			t.Error("instance was not cached before expiring")
+		}
+	})
+
+	time.Sleep(60 * time.Millisecond)
+	c.Invoke(func(tok Token) {
+		if tok.Value != 2 {
+			t.Error("expired instance was not rebuilt")
+		}
+	})
+	if disposed != 1 {
+		t.Errorf("expired instance was disposed %d times, expected 1", disposed)
+	}
+
+	c.Close()
+	if disposed != 2 {
+		t.Error("instance was not disposed when closing the container")
+	}
+}
+
+func TestExpiringMaxUses(t *testing.T) {
+	c := New()
+	built := 0
+	c.AddExpiring(ExpiryPolicy{MaxUses: 2}, func() Token {
+		built++
+		return Token{Value: built}
+	})
+
+	var values []int
+	for i := 0; i < 5; i++ {
+		c.Invoke(func(tok Token) { values = append(values, tok.Value) })
+	}
+
+	if !reflect.DeepEqual(values, []int{1, 1, 2, 2, 3}) {
+		t.Errorf("wrong instances %v", values)
+	}
+}
+
+func TestExpiringConcurrent(t *testing.T) {
+	c := New()
+	var mu sync.Mutex
+	built := 0
+	c.AddExpiring(ExpiryPolicy{TTL: time.Hour}, func() Token {
+		mu.Lock()
+		defer mu.Unlock()
+		built++
+		return Token{Value: built}
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Invoke(func(Token) {})
+		}()
+	}
+	wg.Wait()
+
+	if built != 1 {
+		t.Errorf("instance was built %d times concurrently", built)
+	}
+}
+
+func TestExpiringErrors(t *testing.T) {
+	c := New()
+	if err := c.AddExpiring(ExpiryPolicy{}, func() Token { return Token{} }); err == nil {
+		t.Error("policy without limits did not return error")
+	}
+	if err := c.AddWithScope(ScopeExpiring, func() Token { return Token{} }); err == nil {
+		t.Error("ScopeExpiring without a policy did not return error")
+	}
+
+	c.AddExpiring(ExpiryPolicy{MaxUses: 1}, func(tok Token) Token { return tok })
+	var cycleErr *CycleError
+	if err := c.Invoke(func(Token) {}); !errors.As(err, &cycleErr) {
+		t.Errorf("cycle was not detected, got %v", err)
+	}
+}
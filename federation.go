@@ -0,0 +1,149 @@
+package cosmo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Member is a container taking part in a Federation.
+type Member struct {
+	// Name identifies the container in errors, like "app" or "plugin/billing".
+	Name      string
+	Container *Container
+}
+
+// Federation resolves types across an ordered list of independent containers. Each
+// type is resolved, with all its dependencies, by the first container that has a
+// provider for it, so modules keep their own containers and the application decides
+// which one wins.
+//
+//	f := cosmo.NewFederation(
+//		cosmo.Member{Name: "app", Container: app},
+//		cosmo.Member{Name: "plugin", Container: plugin},
+//		cosmo.Member{Name: "defaults", Container: defaults},
+//	)
+//	err := f.Invoke(func(logger Logger, store Store) { ... })
+type Federation struct {
+	members []Member
+}
+
+// NewFederation returns a federation consulting the members in the given order.
+func NewFederation(members ...Member) *Federation {
+	return &Federation{members: members}
+}
+
+// FederationError is returned when a federation fails to resolve a type. It records
+// which container was asked to build it, or which ones were consulted when none
+// of them has a provider.
+type FederationError struct {
+	Type reflect.Type
+	// Container is the name of the container that failed to build the type, it's
+	// empty when no container has a provider for it.
+	Container string
+	// Consulted holds the names of the containers consulted, in order.
+	Consulted []string
+	Err       error
+}
+
+func (e *FederationError) Error() string {
+	if e.Container == "" {
+		return fmt.Sprintf("%v (consulted %s)", e.Err, strings.Join(e.Consulted, ", "))
+	}
+	return fmt.Sprintf("container %q: %v", e.Container, e.Err)
+}
+
+func (e *FederationError) Unwrap() error {
+	return e.Err
+}
+
+// Source returns the name of the container that resolves t.
+func (f *Federation) Source(t reflect.Type) (string, bool) {
+	m, ok := f.member(t)
+	return m.Name, ok
+}
+
+// member returns the first member with a provider for t. Optional and lazy
+// dependencies are looked up by the type they wrap.
+func (f *Federation) member(t reflect.Type) (Member, bool) {
+	dep := dependencyOf(t)
+	for _, m := range f.members {
+		if m.Container.HasProvider(dep) {
+			return m, true
+		}
+	}
+	return Member{}, false
+}
+
+// Resolve returns an instance of t built by the first container that has a provider
+// for it.
+func (f *Federation) Resolve(t reflect.Type) (reflect.Value, error) {
+	if len(f.members) == 0 {
+		return reflect.Value{}, &NoProviderError{Type: t}
+	}
+
+	m, ok := f.member(t)
+	if !ok {
+		// Optional dependencies and contexts are resolved by any container.
+		if t != contextType && dependencyOf(t) == t {
+			return reflect.Value{}, &FederationError{Type: t, Consulted: f.names(), Err: &NoProviderError{Type: t}}
+		}
+		m = f.members[0]
+	}
+
+	v, err := m.Container.resolve(m.Container.Context(), t)
+	if err != nil {
+		return reflect.Value{}, &FederationError{Type: t, Container: m.Name, Err: err}
+	}
+	return v, nil
+}
+
+// Invoke runs the function like Container.Invoke, resolving each argument with
+// Federation.Resolve. The variadic argument stays empty when no container has a
+// provider for it.
+func (f *Federation) Invoke(fn any) error {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return errors.New("invoke expects a function")
+	}
+
+	t := v.Type()
+	p := planOf(t)
+	args := make([]reflect.Value, len(p.args))
+	for i := range args {
+		val, err := f.resolveArg(p, i)
+		if err != nil {
+			return err
+		}
+		args[i] = val
+	}
+
+	out := callFunc(v, args)
+	if n := len(out); n > 0 && t.Out(n-1) == errorType {
+		if err := out[n-1]; !err.IsNil() {
+			return err.Interface().(error)
+		}
+	}
+
+	return nil
+}
+
+// resolveArg resolves the argument i of the plan like Container.resolveArg.
+func (f *Federation) resolveArg(p *plan, i int) (reflect.Value, error) {
+	t := p.args[i]
+	if p.variadic && i == len(p.args)-1 {
+		if _, ok := f.member(t); !ok {
+			return reflect.Zero(t), nil
+		}
+	}
+	return f.Resolve(t)
+}
+
+func (f *Federation) names() []string {
+	names := make([]string, len(f.members))
+	for i, m := range f.members {
+		names[i] = m.Name
+	}
+	return names
+}
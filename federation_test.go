@@ -0,0 +1,92 @@
+package cosmo
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFederation(t *testing.T) {
+	app, defaults := New(), New()
+	app.Add(func() Config { return Config{URL: "app"} })
+	defaults.Add(func() Config { return Config{URL: "defaults"} })
+	defaults.Add(func(cfg Config) DBService { return &SQLDBService{Config: cfg} })
+
+	f := NewFederation(
+		Member{Name: "app", Container: app},
+		Member{Name: "defaults", Container: defaults},
+	)
+
+	err := f.Invoke(func(cfg Config, db DBService, missing Optional[Handler]) {
+		if cfg.URL != "app" {
+			t.Error("config was not resolved by the first container")
+		}
+		if db.(*SQLDBService).Config.URL != "defaults" {
+			t.Error("dependencies were not resolved by the container of the provider")
+		}
+		if missing.Ok {
+			t.Error("missing optional dependency was resolved")
+		}
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if name, _ := f.Source(reflect.TypeFor[DBService]()); name != "defaults" {
+		t.Errorf("expected DBService from defaults, got %q", name)
+	}
+
+	_, err = f.Resolve(reflect.TypeFor[Handler]())
+	if !errors.Is(err, ErrNoProvider) || !strings.Contains(err.Error(), "consulted app, defaults") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestFederationProvenance(t *testing.T) {
+	app, plugin := New(), New()
+	plugin.Add(func(h Handler) DBService { return &SQLDBService{} })
+
+	f := NewFederation(
+		Member{Name: "app", Container: app},
+		Member{Name: "plugin", Container: plugin},
+	)
+
+	err := f.Invoke(func(db DBService) {})
+	var fe *FederationError
+	if !errors.As(err, &fe) || fe.Container != "plugin" {
+		t.Fatalf("expected error from the plugin container, got %v", err)
+	}
+	if !errors.Is(err, ErrNoProvider) {
+		t.Error("federation error does not wrap the container error")
+	}
+}
+
+func TestFederationVariadic(t *testing.T) {
+	app, plugin := New(), New()
+	app.Add(func() string { return "app" })
+
+	f := NewFederation(
+		Member{Name: "app", Container: app},
+		Member{Name: "plugin", Container: plugin},
+	)
+
+	err := f.Invoke(func(s string, opts ...Option) {
+		if len(opts) != 0 {
+			t.Error("variadic argument without providers was not empty")
+		}
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	plugin.AddToGroup("options", func() Option { return func(s *Server) {} })
+	err = f.Invoke(func(s string, opts ...Option) {
+		if len(opts) != 1 {
+			t.Errorf("expected the option of the plugin group, got %d", len(opts))
+		}
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+}
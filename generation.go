@@ -0,0 +1,93 @@
+package cosmo
+
+import "context"
+
+// instances returns the cache of the current generation.
+func (c *Container) instances() *cache {
+	return c.cache.Load()
+}
+
+// Generation returns how many times the container singletons were reloaded.
+func (c *Container) Generation() uint64 {
+	return c.generation.Load()
+}
+
+// Reload builds a new generation of every singleton in the background, while the
+// current generation keeps being served. Once all the singletons are built, the
+// container atomically switches to the new generation and disposes the old one,
+// running its cleanup functions.
+//
+// If any constructor fails, the partially built generation is disposed and the
+// current generation is kept. Instances resolved before the switch keep referencing
+// the old generation, so consumers should resolve them again after a reload.
+func (c *Container) Reload() error {
+	next := newCache()
+	ctx := context.WithValue(c.Context(), generationKey{}, &staged{container: c, cache: next})
+	if err := c.build(ctx); err != nil {
+		next.dispose()
+		return err
+	}
+
+	old := c.cache.Swap(next)
+	c.generation.Add(1)
+	return old.dispose()
+}
+
+// generationKey is the context key of the generation being built by Reload.
+type generationKey struct{}
+
+// staged is a generation of the instances of container that isn't served yet.
+type staged struct {
+	container *Container
+	cache     *cache
+}
+
+// cacheOf returns the cache the resolutions made with ctx use: the generation being
+// built by Reload, or the current one. Resolutions delegated to the parent use the
+// cache of the parent.
+func (c *Container) cacheOf(ctx context.Context) *cache {
+	if s, ok := ctx.Value(generationKey{}).(*staged); ok && s.container == c {
+		return s.cache
+	}
+	return c.instances()
+}
+
+// build constructs every singleton registered in the container.
+func (c *Container) build(ctx context.Context) error {
+	for _, t := range c.providedTypes() {
+		provider, ok := c.provider(t)
+		if !ok || provider.Scope != ScopeSingleton {
+			continue
+		}
+		if _, err := c.resolve(ctx, t); err != nil {
+			return err
+		}
+	}
+
+	for name, provider := range c.named {
+		if provider.Scope != ScopeSingleton {
+			continue
+		}
+		if _, err := c.resolveNamed(ctx, name); err != nil {
+			return err
+		}
+	}
+
+	for name, g := range c.groups {
+		for i, provider := range g.specs {
+			if provider.Scope != ScopeSingleton {
+				continue
+			}
+			if _, ok := c.cacheOf(ctx).getGroup(name, i); ok {
+				continue
+			}
+			val, err := c.call(ctx, provider)
+			if err != nil {
+				return err
+			}
+			c.cacheOf(ctx).setGroup(name, i, val)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package cosmo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestReload(t *testing.T) {
+	c := New()
+	version := 0
+	disposed := 0
+	failing := false
+
+	c.AddSingleton(func() (Config, func(), error) {
+		if failing {
+			return Config{}, nil, errors.New("config unavailable")
+		}
+		version++
+		return Config{URL: DBURL}, func() { disposed++ }, nil
+	})
+
+	c.Invoke(func(cfg Config) {})
+	if err := c.Reload(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if version != 2 || disposed != 1 {
+		t.Errorf("reload built %d generations and disposed %d", version, disposed)
+	}
+	if c.Generation() != 1 {
+		t.Errorf("wrong generation %d", c.Generation())
+	}
+
+	c.Invoke(func(cfg Config) {})
+	if version != 2 {
+		t.Error("singleton of the new generation was built again")
+	}
+
+	failing = true
+	if err := c.Reload(); err == nil {
+		t.Error("failed reload did not return error")
+	}
+	if disposed != 1 || c.Generation() != 1 {
+		t.Error("failed reload replaced the current generation")
+	}
+}
+
+func TestReloadConcurrentResolution(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() Config {
+		return Config{
+			URL: DBURL,
+		}
+	})
+	c.Add(func(cfg Config) DBService {
+		return &SQLDBService{
+			Config: cfg,
+		}
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if err := c.Invoke(func(db DBService) {}); err != nil {
+					t.Error(err.Error())
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 10; i++ {
+		if err := c.Reload(); err != nil {
+			t.Error(err.Error())
+		}
+	}
+	wg.Wait()
+}
+
+type reloadInjected struct {
+	container *Container
+}
+
+func (r *reloadInjected) Inject(ctx context.Context, c *Container) error {
+	r.container = c
+	return nil
+}
+
+func TestReloadBuildsWithContainer(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() *reloadInjected { return &reloadInjected{} })
+
+	if err := c.Reload(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	c.Invoke(func(r *reloadInjected) {
+		if r.container != c {
+			t.Error("Inject received another container after the reload")
+		}
+	})
+}
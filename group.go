@@ -0,0 +1,143 @@
+package cosmo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// group holds the providers registered under the same group name, in registration
+// order. All the providers of a group must build the same type.
+type group struct {
+	typ   reflect.Type
+	specs []Spec
+}
+
+// AddToGroupWithScope adds the constructor to the named group using the specified scope.
+// Groups allow many providers of the same type to coexist, and they are resolved
+// together when a consumer asks for a slice of that type.
+func (c *Container) AddToGroupWithScope(scope Scope, name string, constructor any) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	if err := checkScope(scope); err != nil {
+		return err
+	}
+	t, v, err := spec(constructor)
+	if err != nil {
+		return err
+	}
+
+	g, ok := c.groups[name]
+	if !ok {
+		g = &group{typ: t}
+		c.groups[name] = g
+	}
+
+	if g.typ != t {
+		return fmt.Errorf("group %q expects constructors of type %v, got %v", name, g.typ, t)
+	}
+
+	g.specs = append(g.specs, Spec{
+		Type:   t,
+		Value:  v,
+		Scope:  scope,
+		Group:  name,
+		order:  c.registrations.Add(1),
+		module: c.module,
+	})
+
+	return nil
+}
+
+// AddToGroup adds the constructor to the named group with ScopeTransient.
+//
+//	c.AddToGroup("validators", NewEmailValidator)
+//	c.AddToGroup("validators", NewPhoneValidator)
+//	c.Invoke(func(validators []Validator) { ... })
+func (c *Container) AddToGroup(name string, constructor any) error {
+	if err := c.AddToGroupWithScope(ScopeTransient, name, constructor); err != nil {
+		return err
+	}
+	return nil
+}
+
+// resolveGroup builds every member of the named group and returns them as a slice.
+// Members registered with ScopeSingleton are only built once.
+func (c *Container) resolveGroup(ctx context.Context, name string) (reflect.Value, error) {
+	g, ok := c.groups[name]
+	if !ok {
+		if c.parent != nil {
+			return c.parent.resolveGroup(ctx, name)
+		}
+		return reflect.Value{}, &NoProviderError{Name: name, Chain: Chain(ctx)}
+	}
+
+	out := reflect.MakeSlice(reflect.SliceOf(g.typ), 0, len(g.specs))
+	for i, provider := range g.specs {
+		if inst, ok := c.cacheOf(ctx).getGroup(name, i); ok {
+			c.metrics.CacheHit(g.typ)
+			out = reflect.Append(out, inst)
+			continue
+		}
+
+		var val reflect.Value
+		var err error
+		if provider.Scope == ScopeSingleton {
+			slot := groupSlot{name: name, index: i}
+			val, err = c.buildOnce(ctx, slot, slot, provider)
+		} else {
+			val, err = c.call(ctx, provider)
+		}
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		out = reflect.Append(out, val)
+	}
+
+	return out, nil
+}
+
+// resolveGroups returns the members of every group whose members are of type t,
+// merged in the order of the group names. The boolean result reports whether any
+// group of that type exists.
+func (c *Container) resolveGroups(ctx context.Context, t reflect.Type) (reflect.Value, bool, error) {
+	var names []string
+	for name, g := range c.groups {
+		if g.typ == t {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return reflect.Value{}, false, nil
+	}
+
+	sort.Strings(names)
+
+	out := reflect.MakeSlice(reflect.SliceOf(t), 0, 0)
+	for _, name := range names {
+		members, err := c.resolveGroup(ctx, name)
+		if err != nil {
+			return reflect.Value{}, true, err
+		}
+		out = reflect.AppendSlice(out, members)
+	}
+
+	return out, true, nil
+}
+
+// CloseGroup disposes the instances built for the members of the named group, running
+// the cleanup functions returned by their constructors and closing the members that
+// implement io.Closer, in the reverse order they were built. Only singleton members
+// are disposed, since the container doesn't keep transient ones, and they're built
+// again on the next resolution, so a subsystem can be stopped and restarted without
+// affecting the rest of the container.
+func (c *Container) CloseGroup(name string) error {
+	if _, ok := c.groups[name]; !ok {
+		return &NoProviderError{Name: name}
+	}
+	return c.instances().disposeGroup(name)
+}
@@ -0,0 +1,188 @@
+package cosmo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type Validator interface {
+	Validate(string) error
+}
+
+type NotEmptyValidator struct{}
+
+func (v *NotEmptyValidator) Validate(s string) error {
+	if s == "" {
+		return errors.New("empty value")
+	}
+	return nil
+}
+
+type MaxLenValidator struct {
+	Max int
+}
+
+func (v *MaxLenValidator) Validate(s string) error {
+	if len(s) > v.Max {
+		return errors.New("value too long")
+	}
+	return nil
+}
+
+type ToBindGroup struct {
+	Validators []Validator
+}
+
+func TestGroup(t *testing.T) {
+	c := New()
+	singletonConstructorCallTimes := 0
+
+	err := c.AddToGroupWithScope(ScopeSingleton, "validators", func() Validator {
+		singletonConstructorCallTimes++
+		return &NotEmptyValidator{}
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	err = c.AddToGroup("validators", func() Validator {
+		return &MaxLenValidator{Max: 3}
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	err = c.Invoke(func(validators []Validator) {
+		if len(validators) != 2 {
+			t.Errorf("expected 2 validators, got %d", len(validators))
+		}
+		if err := validators[1].Validate("abcd"); err == nil {
+			t.Error("validators were not injected in registration order")
+		}
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	var bnd ToBindGroup
+	if err = c.Bind(&bnd); err != nil {
+		t.Error(err.Error())
+	}
+	if len(bnd.Validators) != 2 {
+		t.Errorf("expected 2 validators, got %d", len(bnd.Validators))
+	}
+	if singletonConstructorCallTimes != 1 {
+		t.Errorf("Singleton constructor was called %d times", singletonConstructorCallTimes)
+	}
+}
+
+func TestGroupWrongType(t *testing.T) {
+	c := New()
+	c.AddToGroup("validators", func() Validator {
+		return &NotEmptyValidator{}
+	})
+	if err := c.AddToGroup("validators", func() Config { return Config{} }); err == nil {
+		t.Error("group accepted constructor of a different type")
+	}
+}
+
+type RouteHandler struct {
+	Name   string
+	closed *[]string
+}
+
+func (h *RouteHandler) Close() error {
+	*h.closed = append(*h.closed, h.Name)
+	return nil
+}
+
+func TestCloseGroup(t *testing.T) {
+	c := New()
+	var closed []string
+	built := 0
+
+	c.AddToGroupWithScope(ScopeSingleton, "routes", func() *RouteHandler {
+		built++
+		return &RouteHandler{Name: "users", closed: &closed}
+	})
+	c.AddToGroupWithScope(ScopeSingleton, "routes", func() (*RouteHandler, func()) {
+		return &RouteHandler{Name: "orders", closed: &closed}, func() {
+			closed = append(closed, "orders cleanup")
+		}
+	})
+	c.AddSingleton(func() (Config, func()) {
+		return Config{}, func() { closed = append(closed, "config") }
+	})
+
+	err := c.Invoke(func(routes []*RouteHandler, cfg Config) {})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err = c.CloseGroup("routes"); err != nil {
+		t.Error(err.Error())
+	}
+
+	expected := []string{"orders", "orders cleanup", "users"}
+	if !reflect.DeepEqual(closed, expected) {
+		t.Errorf("group was disposed in the wrong order: %v", closed)
+	}
+
+	c.Invoke(func(routes []*RouteHandler) {})
+	if built != 2 {
+		t.Error("singleton members were not built again after the group was closed")
+	}
+
+	closed = nil
+	c.Close()
+	if !reflect.DeepEqual(closed, []string{"orders", "orders cleanup", "users", "config"}) {
+		t.Errorf("container close disposed %v", closed)
+	}
+
+	if err = c.CloseGroup("unknown"); err == nil {
+		t.Error("closing an unknown group did not return error")
+	}
+}
+
+type Option func(*Server)
+
+type Server struct {
+	Options int
+}
+
+func NewServer(cfg Config, opts ...Option) *Server {
+	s := &Server{}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func TestVariadicConstructor(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() Config { return Config{} })
+	c.Add(NewServer)
+
+	err := c.Invoke(func(s *Server) {
+		if s.Options != 0 {
+			t.Error("variadic argument without providers was not empty")
+		}
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	withTimeout := func() Option { return func(s *Server) { s.Options++ } }
+	c.AddToGroup("options", withTimeout)
+	c.AddToGroup("options", withTimeout)
+
+	err = c.Invoke(func(s *Server) {
+		if s.Options != 2 {
+			t.Errorf("expected the 2 options of the group, got %d", s.Options)
+		}
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+}
@@ -0,0 +1,109 @@
+package cosmo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Injectable is implemented by types that resolve their collaborators themselves,
+// after being constructed. The container calls Inject on every instance it builds
+// that implements it, and fails the resolution if it returns an error. Resolutions
+// made with ctx, through Container.InvokeCtx, continue the resolution of the
+// instance, so a dependency cycle is reported instead of recursing.
+//
+//	func (s *Server) Inject(ctx context.Context, c *cosmo.Container) error {
+//		return c.InvokeCtx(ctx, func(log Logger) { s.log = log })
+//	}
+type Injectable interface {
+	Inject(ctx context.Context, c *Container) error
+}
+
+// InjectSetters makes the container call the setter methods of the instances of typ,
+// given as accepted by TypeOf, after building them, resolving their arguments. It
+// integrates types whose constructors can't be changed but expose setters for their
+// collaborators:
+//
+//	c.Add(thirdparty.NewClient)
+//	c.InjectSetters((*thirdparty.Client)(nil), "SetLogger", "SetTransport")
+//
+// Without methods, every method starting with Set is called. Setters must return
+// nothing or an error, and are called before the decorators of typ are applied. A
+// nil type returns an error.
+func (c *Container) InjectSetters(typ any, methods ...string) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	t := TypeOf(typ)
+	if t == nil {
+		return errors.New("can't inject the setters of a nil type")
+	}
+
+	if len(methods) == 0 {
+		for i := 0; i < t.NumMethod(); i++ {
+			if name := t.Method(i).Name; strings.HasPrefix(name, "Set") {
+				methods = append(methods, name)
+			}
+		}
+		if len(methods) == 0 {
+			return fmt.Errorf("%v has no setter methods", t)
+		}
+	}
+
+	for _, name := range methods {
+		m, ok := t.MethodByName(name)
+		if !ok {
+			return fmt.Errorf("%v has no method %s", t, name)
+		}
+		// The method type of interfaces has no receiver argument.
+		out := m.Type.NumOut()
+		if out > 1 || out == 1 && m.Type.Out(0) != errorType {
+			return fmt.Errorf("setter %v.%s must return nothing or an error", t, name)
+		}
+	}
+
+	c.setters[t] = append(c.setters[t], methods...)
+	return c.EvictInstance(t)
+}
+
+// inject calls the setters registered for t on the instance, and its Inject method
+// if it implements Injectable.
+func (c *Container) inject(ctx context.Context, t reflect.Type, instance reflect.Value) error {
+	if !instance.IsValid() || isNil(instance) {
+		return nil
+	}
+
+	for _, name := range c.setters[t] {
+		setter := instance.MethodByName(name)
+		p := planOf(setter.Type())
+		args, err := c.resolveArgs(ctx, p)
+		if err != nil {
+			return err
+		}
+
+		out, err := c.construct(ctx, t, setter, args)
+		if err != nil {
+			return err
+		}
+		if len(out) == 1 && !out[0].IsNil() {
+			return fmt.Errorf("%s: %w", name, out[0].Interface().(error))
+		}
+	}
+
+	injectable, ok := instance.Interface().(Injectable)
+	if !ok {
+		return nil
+	}
+	return injectable.Inject(ctx, c)
+}
+
+// isNil reports whether v is a nil pointer, interface, map, slice, channel or func.
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+		return v.IsNil()
+	}
+	return false
+}
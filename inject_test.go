@@ -0,0 +1,150 @@
+package cosmo
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type Notifier struct {
+	config   Config
+	db       DBService
+	injected bool
+}
+
+func (m *Notifier) SetConfig(cfg Config) {
+	m.config = cfg
+}
+
+func (m *Notifier) SetDB(db DBService) error {
+	if db == nil {
+		return errors.New("nil db")
+	}
+	m.db = db
+	return nil
+}
+
+func (m *Notifier) URL() string {
+	return m.config.URL
+}
+
+func (m *Notifier) Inject(ctx context.Context, c *Container) error {
+	m.injected = true
+	return nil
+}
+
+func TestInjectSetters(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() Config { return Config{URL: DBURL} })
+	c.Add(func(cfg Config) DBService { return &SQLDBService{Config: cfg} })
+	c.Add(func() *Notifier { return &Notifier{} })
+
+	if err := c.InjectSetters((*Notifier)(nil)); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err := c.Invoke(func(m *Notifier) {
+		if m.config.URL != DBURL || m.db == nil {
+			t.Error("setters were not called")
+		}
+		if !m.injected {
+			t.Error("Inject was not called")
+		}
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestInjectSettersErrors(t *testing.T) {
+	c := New()
+	c.Add(func() *Notifier { return &Notifier{} })
+	c.Add(func() DBService { return nil })
+
+	if err := c.InjectSetters((*Notifier)(nil), "SetCache"); err == nil {
+		t.Error("unknown setter did not return error")
+	}
+	if err := c.InjectSetters((*Notifier)(nil), "URL"); err == nil {
+		t.Error("setter with a wrong signature did not return error")
+	}
+	if err := c.InjectSetters(nil); err == nil {
+		t.Error("nil type did not return error")
+	}
+
+	c.InjectSetters((*Notifier)(nil), "SetDB")
+	var constructorErr *ConstructorError
+	if err := c.Invoke(func(*Notifier) {}); !errors.As(err, &constructorErr) {
+		t.Errorf("setter error was not returned, got %v", err)
+	}
+}
+
+type failingInjectable struct{}
+
+func (failingInjectable) Inject(ctx context.Context, c *Container) error {
+	return errors.New("inject failed")
+}
+
+func TestInjectableError(t *testing.T) {
+	c := New()
+	c.Add(func() failingInjectable { return failingInjectable{} })
+
+	if err := c.Invoke(func(failingInjectable) {}); err == nil {
+		t.Error("Inject error was not returned")
+	}
+}
+
+type selfInjectable struct{}
+
+func (s *selfInjectable) Inject(ctx context.Context, c *Container) error {
+	return c.InvokeCtx(ctx, func(*selfInjectable) {})
+}
+
+func TestInjectableCycle(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() *selfInjectable { return &selfInjectable{} })
+
+	if err := c.Invoke(func(*selfInjectable) {}); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+type setterInterface interface {
+	SetConfig(cfg Config)
+}
+
+func TestInjectSettersNilInstance(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() Config { return Config{URL: DBURL} })
+	c.Add(func() setterInterface { return nil })
+	c.InjectSetters((*setterInterface)(nil))
+
+	if err := c.Invoke(func(setterInterface) {}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestInjectSettersClone(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() Config { return Config{URL: DBURL} })
+	c.AddSingleton(func() DBService { return &SQLDBService{} })
+	c.Add(func() *Notifier { return &Notifier{} })
+	// The setters slice grows past its length, so appends could share the array.
+	for range 3 {
+		c.InjectSetters((*Notifier)(nil), "SetConfig")
+	}
+
+	clone := c.Clone()
+	c.InjectSetters((*Notifier)(nil), "SetDB")
+	clone.InjectSetters((*Notifier)(nil), "SetConfig")
+
+	c.Invoke(func(m *Notifier) {
+		if m.db == nil {
+			t.Error("setter of the container was replaced by the clone")
+		}
+	})
+	clone.Invoke(func(m *Notifier) {
+		if m.db != nil {
+			t.Error("setter added to the container was called by the clone")
+		}
+	})
+}
@@ -0,0 +1,170 @@
+package cosmo
+
+import (
+	"reflect"
+	"runtime"
+	"sort"
+)
+
+// ProviderInfo describes a provider registered in the container.
+type ProviderInfo struct {
+	Type  reflect.Type
+	Scope Scope
+	// Name is set for providers added with AddNamed.
+	Name string
+	// Group is set for providers added to a group.
+	Group string
+	// Constructor is the name of the constructor function.
+	Constructor string
+	// Value is the constructor function.
+	Value reflect.Value
+	// Dependencies are the types the constructor and the decorators of the type depend on.
+	Dependencies []reflect.Type
+	// Order is the position of the provider in the registrations, starting at 1.
+	Order uint64
+	// Materialized reports whether a singleton instance is cached.
+	Materialized bool
+	// Profiles holds the profiles of a provider added with AddWhen.
+	Profiles []string
+	// Active reports whether the provider is used to resolve its type. It's false for
+	// conditional providers of inactive profiles, and for the providers replaced by
+	// active ones.
+	Active bool
+}
+
+// Providers returns the providers registered in the container, in registration
+// order. It's meant for admin and debug endpoints listing what the app wired.
+func (c *Container) Providers() []ProviderInfo {
+	cache := c.instances()
+	var infos []ProviderInfo
+
+	for t, provider := range c.providers {
+		_, materialized := cache.get(t)
+		infos = append(infos, c.providerInfo(provider, materialized))
+	}
+
+	for _, conditional := range c.conditional {
+		for _, provider := range conditional {
+			_, materialized := cache.get(provider.Type)
+			infos = append(infos, c.providerInfo(provider, materialized))
+		}
+	}
+
+	for name, provider := range c.named {
+		_, materialized := cache.getNamed(name)
+		infos = append(infos, c.providerInfo(provider, materialized))
+	}
+
+	for name, g := range c.groups {
+		for i, provider := range g.specs {
+			_, materialized := cache.getGroup(name, i)
+			infos = append(infos, c.providerInfo(provider, materialized))
+		}
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Order < infos[j].Order
+	})
+
+	return infos
+}
+
+func (c *Container) providerInfo(provider Spec, materialized bool) ProviderInfo {
+	active := true
+	if provider.Name == "" && provider.Group == "" {
+		current, _ := c.provider(provider.Type)
+		active = current.order == provider.order
+	}
+
+	return ProviderInfo{
+		Type:         provider.Type,
+		Scope:        provider.Scope,
+		Name:         provider.Name,
+		Group:        provider.Group,
+		Constructor:  funcName(provider.Value),
+		Value:        provider.Value,
+		Dependencies: c.dependencies(provider),
+		Order:        provider.order,
+		Materialized: materialized && active,
+		Profiles:     provider.profiles,
+		Active:       active,
+	}
+}
+
+// Construct calls the constructor of the provider described by info, resolving its
+// dependencies from the container, and returns the value it builds. The value isn't
+// cached, even for singletons, which allows checking every implementation of a type.
+func (c *Container) Construct(info ProviderInfo) (any, error) {
+	provider := Spec{
+		Type:  info.Type,
+		Value: info.Value,
+		Scope: ScopeTransient,
+		Name:  info.Name,
+		Group: info.Group,
+	}
+	for i := 0; i < info.Value.Type().NumOut(); i++ {
+		if info.Value.Type().Out(i) == info.Type {
+			provider.index = i
+			break
+		}
+	}
+
+	v, err := c.call(c.Context(), provider)
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+// Decorators returns the decorators registered for t, in the order they are applied.
+func (c *Container) Decorators(t reflect.Type) []reflect.Value {
+	return append([]reflect.Value(nil), c.decorators[t]...)
+}
+
+// DependenciesOf returns the types the provider of t depends on. Optional and lazy
+// dependencies are unwrapped.
+func (c *Container) DependenciesOf(t reflect.Type) ([]reflect.Type, error) {
+	provider, ok := c.provider(t)
+	if !ok {
+		return nil, &NoProviderError{Type: t}
+	}
+	return c.dependencies(provider), nil
+}
+
+// HasProvider reports whether the container can build the type, because it has a
+// provider, a cached instance, or a group of its element type, for slices.
+//
+// Children, created with Container.Child, report the providers of their parent too.
+func (c *Container) HasProvider(t reflect.Type) bool {
+	return c.hasLocal(t) || c.parent != nil && c.parent.HasProvider(t)
+}
+
+// hasLocal reports whether the container can build the type without its parent.
+func (c *Container) hasLocal(t reflect.Type) bool {
+	if _, ok := c.instances().get(t); ok {
+		return true
+	}
+	if _, ok := c.provider(t); ok {
+		return true
+	}
+	if _, ok := c.adaptation(t); ok {
+		return true
+	}
+	if t.Kind() == reflect.Slice {
+		for _, g := range c.groups {
+			if g.typ == t.Elem() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// funcName returns the name of the function held by v.
+func funcName(v reflect.Value) string {
+	fn := runtime.FuncForPC(v.Pointer())
+	if fn == nil {
+		return v.Type().String()
+	}
+	return fn.Name()
+}
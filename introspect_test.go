@@ -0,0 +1,60 @@
+package cosmo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestProviders(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() Config { return Config{} })
+	c.Add(func(cfg Config) DBService { return &SQLDBService{} })
+	c.AddToGroup("validators", func() Validator { return &NotEmptyValidator{} })
+
+	if err := c.Invoke(func(cfg Config) {}); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	infos := c.Providers()
+	if len(infos) != 3 {
+		t.Fatalf("expected 3 providers, got %d", len(infos))
+	}
+
+	cfg, db, v := infos[0], infos[1], infos[2]
+	if cfg.Type != reflect.TypeOf(Config{}) || !cfg.Materialized || cfg.Scope != ScopeSingleton {
+		t.Errorf("unexpected config provider info: %+v", cfg)
+	}
+	if db.Type != reflect.TypeOf((*DBService)(nil)).Elem() || db.Materialized {
+		t.Errorf("unexpected db provider info: %+v", db)
+	}
+	if !reflect.DeepEqual(db.Dependencies, []reflect.Type{reflect.TypeOf(Config{})}) {
+		t.Errorf("unexpected db dependencies: %v", db.Dependencies)
+	}
+	if v.Group != "validators" || db.Order >= v.Order {
+		t.Errorf("unexpected group provider info: %+v", v)
+	}
+	if cfg.Constructor == "" {
+		t.Error("constructor name is empty")
+	}
+}
+
+func TestDependenciesOf(t *testing.T) {
+	c := New()
+	c.Add(func(cfg Config) DBService { return &SQLDBService{} })
+
+	deps, err := c.DependenciesOf(reflect.TypeOf((*DBService)(nil)).Elem())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !reflect.DeepEqual(deps, []reflect.Type{reflect.TypeOf(Config{})}) {
+		t.Errorf("unexpected dependencies: %v", deps)
+	}
+
+	if _, err := c.DependenciesOf(reflect.TypeOf(Config{})); !errors.Is(err, ErrNoProvider) {
+		t.Errorf("expected ErrNoProvider, got %v", err)
+	}
+	if c.HasProvider(reflect.TypeOf(Config{})) {
+		t.Error("HasProvider reported an unregistered type")
+	}
+}
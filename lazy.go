@@ -0,0 +1,126 @@
+package cosmo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// Lazy defers the resolution of a dependency until Get is called for the first time.
+// It's useful for expensive dependencies that are only needed on rare code paths.
+//
+//	c.Add(func(client cosmo.Lazy[ExpensiveClient]) *ReportService {
+//		return &ReportService{client: client}
+//	})
+//
+//	func (s *ReportService) Export() error {
+//		client, err := s.client.Get()
+//		...
+//	}
+//
+// The resolved value is kept by the Lazy, and the container caches it according to
+// the scope of its provider. Dependencies of type func() (T, error) are injected
+// with the same behavior when there is no provider for the function type.
+type Lazy[T any] struct {
+	state *lazyState
+}
+
+type lazyState struct {
+	once    sync.Once
+	resolve func() (reflect.Value, error)
+	value   reflect.Value
+	err     error
+}
+
+func (s *lazyState) get() (reflect.Value, error) {
+	s.once.Do(func() {
+		s.value, s.err = s.resolve()
+	})
+	return s.value, s.err
+}
+
+// Get resolves the value on the first call, returning the same value, or error, on
+// the following calls.
+func (l Lazy[T]) Get() (T, error) {
+	var zero T
+	if l.state == nil {
+		return zero, errors.New("lazy value was not injected by a container")
+	}
+
+	v, err := l.state.get()
+	if err != nil {
+		return zero, err
+	}
+	value, _ := v.Interface().(T)
+	return value, nil
+}
+
+func (l Lazy[T]) lazyElem() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+func (l *Lazy[T]) setLazy(state *lazyState) {
+	l.state = state
+}
+
+// lazy is implemented by *Lazy[T], it's used to identify lazy dependencies while
+// resolving types.
+type lazy interface {
+	lazyElem() reflect.Type
+	setLazy(state *lazyState)
+}
+
+var lazyType = reflect.TypeFor[lazy]()
+
+// lazyState returns the state of a lazy resolution of t. The resolution keeps the
+// values of ctx, but not its resolution chain, since the value is resolved after the
+// dependent was built.
+func (c *Container) lazyState(ctx context.Context, t reflect.Type) *lazyState {
+	ctx = context.WithValue(ctx, chainKey{}, (*chain)(nil))
+	return &lazyState{
+		resolve: func() (reflect.Value, error) {
+			return c.resolve(ctx, t)
+		},
+	}
+}
+
+// resolveLazy returns a Lazy that resolves the type it wraps.
+func (c *Container) resolveLazy(ctx context.Context, t reflect.Type) reflect.Value {
+	ptr := reflect.New(t)
+	l := ptr.Interface().(lazy)
+	l.setLazy(c.lazyState(ctx, l.lazyElem()))
+	return ptr.Elem()
+}
+
+// isThunk reports whether t is a func() (T, error).
+func isThunk(t reflect.Type) bool {
+	return t.Kind() == reflect.Func && t.NumIn() == 0 && t.NumOut() == 2 && t.Out(1) == errorType
+}
+
+// resolveThunk returns a func() (T, error) that resolves T on the first call.
+func (c *Container) resolveThunk(ctx context.Context, t reflect.Type) reflect.Value {
+	state := c.lazyState(ctx, t.Out(0))
+	return reflect.MakeFunc(t, func([]reflect.Value) []reflect.Value {
+		v, err := state.get()
+		if err != nil {
+			return []reflect.Value{reflect.Zero(t.Out(0)), reflect.ValueOf(&err).Elem()}
+		}
+		return []reflect.Value{v, reflect.Zero(errorType)}
+	})
+}
+
+// dependencyOf returns the type that a dependency of type t resolves, unwrapping
+// optional and lazy dependencies.
+func dependencyOf(t reflect.Type) reflect.Type {
+	ptr := reflect.PointerTo(t)
+	switch {
+	case ptr.Implements(optionalType):
+		return reflect.New(t).Interface().(optional).optionalElem()
+	case ptr.Implements(lazyType):
+		return reflect.New(t).Interface().(lazy).lazyElem()
+	case isThunk(t):
+		return t.Out(0)
+	}
+	return t
+}
@@ -0,0 +1,107 @@
+package cosmo
+
+import (
+	"errors"
+	"testing"
+)
+
+type ReportService struct {
+	DB      Lazy[DBService]
+	Config  func() (Config, error)
+	Missing Lazy[Validator]
+}
+
+func TestLazy(t *testing.T) {
+	c := New()
+	dbBuilt, configBuilt := 0, 0
+
+	c.Add(func() DBService {
+		dbBuilt++
+		return &SQLDBService{}
+	})
+	c.AddSingleton(func() Config {
+		configBuilt++
+		return Config{URL: DBURL}
+	})
+	c.Add(func(db Lazy[DBService], cfg func() (Config, error), validator Lazy[Validator]) *ReportService {
+		return &ReportService{DB: db, Config: cfg, Missing: validator}
+	})
+
+	var svc *ReportService
+	if err := c.Invoke(func(s *ReportService) { svc = s }); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if dbBuilt != 0 || configBuilt != 0 {
+		t.Fatal("lazy dependencies were built eagerly")
+	}
+
+	first, err := svc.DB.Get()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	second, _ := svc.DB.Get()
+	if dbBuilt != 1 || first != second {
+		t.Error("lazy value was not kept after the first call")
+	}
+
+	cfg, err := svc.Config()
+	if err != nil || cfg.URL != DBURL || configBuilt != 1 {
+		t.Error("thunk did not resolve the dependency")
+	}
+
+	if _, err := svc.Missing.Get(); !errors.Is(err, ErrNoProvider) {
+		t.Errorf("expected ErrNoProvider, got %v", err)
+	}
+}
+
+func TestLazyNilInterface(t *testing.T) {
+	c := New()
+	c.Add(func() DBService { return nil })
+
+	err := c.Invoke(func(db Lazy[DBService]) {
+		if v, err := db.Get(); err != nil || v != nil {
+			t.Errorf("wrong lazy value %v, err %v", v, err)
+		}
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestLazyBind(t *testing.T) {
+	c := New()
+	c.Add(func() DBService {
+		return &SQLDBService{}
+	})
+
+	var bnd struct {
+		DB Lazy[DBService]
+	}
+	if err := c.Bind(&bnd); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := bnd.DB.Get(); err != nil {
+		t.Error(err.Error())
+	}
+
+	var zero Lazy[DBService]
+	if _, err := zero.Get(); err == nil {
+		t.Error("zero Lazy did not return error")
+	}
+}
+
+func TestLazyBreaksCycle(t *testing.T) {
+	c := New()
+	c.AddSingleton(func(db Lazy[DBService]) Config {
+		return Config{URL: DBURL}
+	})
+	c.AddSingleton(func(cfg Config) DBService {
+		return &SQLDBService{Config: cfg}
+	})
+
+	err := c.Invoke(func(db DBService, cfg Config) {})
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
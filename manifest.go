@@ -0,0 +1,118 @@
+package cosmo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Catalog maps names to the constructors and decorators that can be referenced
+// by a Manifest. It's registered by the application, so the manifest can only
+// compose functions the application exposes.
+type Catalog map[string]any
+
+// Manifest describes how the constructors of a Catalog are registered in a container,
+// so the composition can change per environment without recompiling.
+//
+//	{
+//		"providers": [
+//			{"constructor": "config", "config": "DBConfig"},
+//			{"constructor": "redisCache", "scope": "singleton"},
+//			{"constructor": "emailValidator", "group": "validators"}
+//		],
+//		"decorators": ["loggingCache"]
+//	}
+type Manifest struct {
+	Providers  []ManifestProvider `json:"providers" yaml:"providers"`
+	Decorators []string           `json:"decorators" yaml:"decorators"`
+}
+
+// ManifestProvider registers one constructor of the catalog. At most one of
+// Name, Group and Config can be set; when none is, the constructor is
+// registered by type.
+type ManifestProvider struct {
+	Constructor string `json:"constructor" yaml:"constructor"`
+	// Scope is "transient" or "singleton", defaults to "transient".
+	Scope string `json:"scope,omitempty" yaml:"scope,omitempty"`
+	// Name registers the constructor with Container.AddNamed.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Group registers the constructor with Container.AddToGroup.
+	Group string `json:"group,omitempty" yaml:"group,omitempty"`
+	// Config registers the constructor with Container.Configure under this key.
+	Config string `json:"config,omitempty" yaml:"config,omitempty"`
+}
+
+// LoadManifest decodes a JSON manifest from r. Manifests in other formats can be
+// decoded into a Manifest by the application and applied with Manifest.Apply.
+func LoadManifest(r io.Reader) (Manifest, error) {
+	var m Manifest
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&m); err != nil {
+		return m, fmt.Errorf("invalid manifest: %w", err)
+	}
+	return m, nil
+}
+
+// Apply registers the providers and decorators of the manifest in the container,
+// using the constructors of the catalog.
+func (m Manifest) Apply(c *Container, catalog Catalog) error {
+	for _, p := range m.Providers {
+		if err := p.apply(c, catalog); err != nil {
+			return fmt.Errorf("manifest provider %q: %w", p.Constructor, err)
+		}
+	}
+
+	for _, name := range m.Decorators {
+		decorator, ok := catalog[name]
+		if !ok {
+			return fmt.Errorf("manifest decorator %q: not found in catalog", name)
+		}
+		if err := c.Decorate(decorator); err != nil {
+			return fmt.Errorf("manifest decorator %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (p ManifestProvider) apply(c *Container, catalog Catalog) error {
+	constructor, ok := catalog[p.Constructor]
+	if !ok {
+		return errors.New("not found in catalog")
+	}
+
+	scope := ScopeTransient
+	if p.Scope != "" {
+		s, err := ParseScope(p.Scope)
+		if err != nil {
+			return err
+		}
+		scope = s
+	}
+
+	targets := 0
+	for _, target := range []string{p.Name, p.Group, p.Config} {
+		if target != "" {
+			targets++
+		}
+	}
+	if targets > 1 {
+		return errors.New("only one of name, group and config can be set")
+	}
+
+	switch {
+	case p.Name != "":
+		return c.AddNamedWithScope(scope, p.Name, constructor)
+	case p.Group != "":
+		return c.AddToGroupWithScope(scope, p.Group, constructor)
+	case p.Config != "":
+		if scope != ScopeSingleton && p.Scope != "" {
+			return errors.New("configurations are always singletons")
+		}
+		return c.Configure(p.Config, constructor)
+	}
+
+	return c.AddWithScope(scope, constructor)
+}
@@ -0,0 +1,95 @@
+package cosmo
+
+import (
+	"strings"
+	"testing"
+)
+
+type MemoryDBService struct{}
+
+func (svc *MemoryDBService) Get() error {
+	return nil
+}
+
+func testCatalog() Catalog {
+	return Catalog{
+		"config": func() Config {
+			return Config{URL: DBURL}
+		},
+		"sqlDB": func(cfg Config) DBService {
+			return &SQLDBService{Config: cfg}
+		},
+		"memoryDB": func() DBService {
+			return &MemoryDBService{}
+		},
+		"loggingDB": func(inner DBService) DBService {
+			return &LoggingDBService{Inner: inner}
+		},
+	}
+}
+
+func TestManifest(t *testing.T) {
+	m, err := LoadManifest(strings.NewReader(`{
+		"providers": [
+			{"constructor": "config", "config": "DBConfig"},
+			{"constructor": "memoryDB", "scope": "singleton"},
+			{"constructor": "sqlDB", "name": "sql"}
+		],
+		"decorators": ["loggingDB"]
+	}`))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	c := New()
+	if err = m.Apply(c, testCatalog()); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if cfg, ok := c.Get("DBConfig").(Config); !ok || cfg.URL != DBURL {
+		t.Error("configuration was not registered")
+	}
+
+	err = c.Invoke(func(db DBService) {
+		logging, ok := db.(*LoggingDBService)
+		if !ok {
+			t.Fatal("decorator was not applied")
+		}
+		if _, ok := logging.Inner.(*MemoryDBService); !ok {
+			t.Error("wrong implementation registered")
+		}
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	var bnd struct {
+		DB DBService `cosmo:"name=sql"`
+	}
+	if err = c.Bind(&bnd); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestManifestErrors(t *testing.T) {
+	manifests := []string{
+		`{"providers": [{"constructor": "unknown"}]}`,
+		`{"providers": [{"constructor": "memoryDB", "scope": "forever"}]}`,
+		`{"providers": [{"constructor": "memoryDB", "name": "a", "group": "b"}]}`,
+		`{"decorators": ["unknown"]}`,
+	}
+
+	for _, manifest := range manifests {
+		m, err := LoadManifest(strings.NewReader(manifest))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if err = m.Apply(New(), testCatalog()); err == nil {
+			t.Errorf("invalid manifest was applied: %s", manifest)
+		}
+	}
+
+	if _, err := LoadManifest(strings.NewReader(`{"provider": []}`)); err == nil {
+		t.Error("manifest with unknown field was loaded")
+	}
+}
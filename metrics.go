@@ -0,0 +1,160 @@
+package cosmo
+
+import (
+	"expvar"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics receives the activity of the container. Implementations must be safe for
+// concurrent use.
+type Metrics interface {
+	// Resolved is called after every resolution of a type.
+	Resolved(t reflect.Type, err error)
+	// CacheHit is called when a resolution is served by a cached singleton.
+	CacheHit(t reflect.Type)
+	// Constructed is called after a constructor runs, with the time it took, not
+	// including the resolution of its dependencies.
+	Constructed(t reflect.Type, took time.Duration, err error)
+}
+
+// SetMetrics sets the Metrics that receive the activity of the container.
+//
+//	stats := cosmo.NewStats()
+//	c.SetMetrics(stats)
+//	stats.Publish("cosmo")
+//	http.Handle("/metrics/cosmo", stats)
+func (c *Container) SetMetrics(m Metrics) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	if m == nil {
+		m = nopMetrics{}
+	}
+	c.metrics = m
+	return nil
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) Resolved(reflect.Type, error)                   {}
+func (nopMetrics) CacheHit(reflect.Type)                          {}
+func (nopMetrics) Constructed(reflect.Type, time.Duration, error) {}
+
+// TypeStats holds the counters of a type.
+type TypeStats struct {
+	Resolutions       uint64
+	ResolutionErrors  uint64
+	CacheHits         uint64
+	Constructions     uint64
+	ConstructionTime  time.Duration
+	ConstructorErrors uint64
+}
+
+// Stats is a Metrics implementation that keeps counters per type in memory. They can
+// be published with expvar, or served in the Prometheus text format.
+type Stats struct {
+	mu    sync.Mutex
+	types map[string]*TypeStats
+}
+
+// NewStats creates an empty Stats.
+func NewStats() *Stats {
+	return &Stats{
+		types: make(map[string]*TypeStats),
+	}
+}
+
+// stats returns the counters of t, the caller must hold s.mu.
+func (s *Stats) stats(t reflect.Type) *TypeStats {
+	key := t.String()
+	ts, ok := s.types[key]
+	if !ok {
+		ts = &TypeStats{}
+		s.types[key] = ts
+	}
+	return ts
+}
+
+func (s *Stats) Resolved(t reflect.Type, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ts := s.stats(t)
+	ts.Resolutions++
+	if err != nil {
+		ts.ResolutionErrors++
+	}
+}
+
+func (s *Stats) CacheHit(t reflect.Type) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats(t).CacheHits++
+}
+
+func (s *Stats) Constructed(t reflect.Type, took time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ts := s.stats(t)
+	ts.Constructions++
+	ts.ConstructionTime += took
+	if err != nil {
+		ts.ConstructorErrors++
+	}
+}
+
+// Snapshot returns a copy of the counters, keyed by the type name.
+func (s *Stats) Snapshot() map[string]TypeStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshot := make(map[string]TypeStats, len(s.types))
+	for key, ts := range s.types {
+		snapshot[key] = *ts
+	}
+	return snapshot
+}
+
+// Publish exports the counters with expvar under name.
+func (s *Stats) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return s.Snapshot()
+	}))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// ServeHTTP writes the counters in the Prometheus text exposition format.
+func (s *Stats) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	snapshot := s.Snapshot()
+	keys := make([]string, 0, len(snapshot))
+	for key := range snapshot {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	metrics := []struct {
+		name  string
+		help  string
+		value func(TypeStats) string
+	}{
+		{"cosmo_resolutions_total", "Resolutions per type.", func(ts TypeStats) string { return fmt.Sprint(ts.Resolutions) }},
+		{"cosmo_resolution_errors_total", "Failed resolutions per type.", func(ts TypeStats) string { return fmt.Sprint(ts.ResolutionErrors) }},
+		{"cosmo_cache_hits_total", "Resolutions served by cached instances per type.", func(ts TypeStats) string { return fmt.Sprint(ts.CacheHits) }},
+		{"cosmo_constructions_total", "Constructor calls per type.", func(ts TypeStats) string { return fmt.Sprint(ts.Constructions) }},
+		{"cosmo_construction_seconds_total", "Time spent in constructors per type.", func(ts TypeStats) string { return fmt.Sprint(ts.ConstructionTime.Seconds()) }},
+		{"cosmo_constructor_errors_total", "Constructor errors per type.", func(ts TypeStats) string { return fmt.Sprint(ts.ConstructorErrors) }},
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	for _, m := range metrics {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", m.name, m.help, m.name)
+		for _, key := range keys {
+			fmt.Fprintf(w, "%s{type=\"%s\"} %s\n", m.name, labelEscaper.Replace(key), m.value(snapshot[key]))
+		}
+	}
+}
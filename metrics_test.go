@@ -0,0 +1,46 @@
+package cosmo
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	c := New()
+	stats := NewStats()
+	c.SetMetrics(stats)
+
+	c.AddSingleton(func() Config {
+		return Config{
+			URL: DBURL,
+		}
+	})
+	c.Add(func(cfg Config) (DBService, error) {
+		return nil, errors.New("connection refused")
+	})
+
+	c.Invoke(func(cfg Config) {})
+	c.Invoke(func(cfg Config) {})
+	if err := c.Invoke(func(db DBService) {}); err == nil {
+		t.Error("constructor error was not returned")
+	}
+
+	snapshot := stats.Snapshot()
+	cfg := snapshot["cosmo.Config"]
+	if cfg.Resolutions != 3 || cfg.CacheHits != 2 || cfg.Constructions != 1 {
+		t.Errorf("wrong Config stats %+v", cfg)
+	}
+
+	db := snapshot["cosmo.DBService"]
+	if db.Resolutions != 1 || db.ResolutionErrors != 1 || db.ConstructorErrors != 1 {
+		t.Errorf("wrong DBService stats %+v", db)
+	}
+
+	rec := httptest.NewRecorder()
+	stats.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if !strings.Contains(rec.Body.String(), `cosmo_cache_hits_total{type="cosmo.Config"} 2`) {
+		t.Errorf("unexpected metrics output:\n%s", rec.Body.String())
+	}
+}
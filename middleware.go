@@ -0,0 +1,66 @@
+package cosmo
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// ResolveFunc resolves the instance of a type. The context is the one received by
+// Container.InvokeCtx, or the container context.
+type ResolveFunc func(ctx context.Context, t reflect.Type) (reflect.Value, error)
+
+// ResolveMiddleware wraps a ResolveFunc, so it can run code before and after the
+// resolution of a type, or replace the resolution altogether.
+type ResolveMiddleware func(next ResolveFunc) ResolveFunc
+
+// UseResolveMiddleware adds a middleware that wraps every resolution made by the container,
+// including the resolution of the constructors dependencies. Middlewares are called in
+// the order they were added, the first one being the outermost.
+//
+//	c.UseResolveMiddleware(func(next cosmo.ResolveFunc) cosmo.ResolveFunc {
+//		return func(ctx context.Context, t reflect.Type) (reflect.Value, error) {
+//			log.Printf("resolving %v", t)
+//			return next(ctx, t)
+//		}
+//	})
+func (c *Container) UseResolveMiddleware(mw ResolveMiddleware) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	c.middlewares = append(c.middlewares, mw)
+	c.resolver = c.buildResolver()
+	return nil
+}
+
+// buildResolver chains the middlewares around Container.resolveType.
+func (c *Container) buildResolver() ResolveFunc {
+	resolver := ResolveFunc(c.resolveType)
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		resolver = c.middlewares[i](resolver)
+	}
+	return resolver
+}
+
+// ResolveHook is called after the resolution of a type, with the time it took and
+// the error returned by the resolution, if any.
+type ResolveHook func(t reflect.Type, took time.Duration, err error)
+
+// OnResolve adds a hook called after every resolution made by the container. The
+// duration of a type includes the resolution of its dependencies, which are
+// reported to the hook too. Hooks are middlewares, for more control over the
+// resolution use Container.UseResolveMiddleware.
+//
+//	c.OnResolve(func(t reflect.Type, took time.Duration, err error) {
+//		log.Printf("resolved %v in %v (err: %v)", t, took, err)
+//	})
+func (c *Container) OnResolve(hook ResolveHook) error {
+	return c.UseResolveMiddleware(func(next ResolveFunc) ResolveFunc {
+		return func(ctx context.Context, t reflect.Type) (reflect.Value, error) {
+			start := time.Now()
+			v, err := next(ctx, t)
+			hook(t, time.Since(start), err)
+			return v, err
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package cosmo
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestResolveMiddleware(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() Config {
+		return Config{
+			URL: DBURL,
+		}
+	})
+	c.Add(func(cfg Config) DBService {
+		return &SQLDBService{
+			Config: cfg,
+		}
+	})
+
+	var calls []string
+	c.UseResolveMiddleware(func(next ResolveFunc) ResolveFunc {
+		return func(ctx context.Context, t reflect.Type) (reflect.Value, error) {
+			calls = append(calls, "outer:"+t.Name())
+			return next(ctx, t)
+		}
+	})
+	c.UseResolveMiddleware(func(next ResolveFunc) ResolveFunc {
+		return func(ctx context.Context, t reflect.Type) (reflect.Value, error) {
+			calls = append(calls, "inner:"+t.Name())
+			return next(ctx, t)
+		}
+	})
+
+	err := c.Invoke(func(db DBService) {})
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	expected := []string{"outer:DBService", "inner:DBService", "outer:Config", "inner:Config"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("unexpected middleware calls %v", calls)
+	}
+}
+
+func TestResolveMiddlewareReplace(t *testing.T) {
+	c := New()
+	c.UseResolveMiddleware(func(next ResolveFunc) ResolveFunc {
+		return func(ctx context.Context, t reflect.Type) (reflect.Value, error) {
+			if t == reflect.TypeOf(Config{}) {
+				return reflect.ValueOf(Config{URL: DBURL}), nil
+			}
+			return next(ctx, t)
+		}
+	})
+
+	err := c.Invoke(func(cfg Config) {
+		if cfg.URL != DBURL {
+			t.Error("wrong value injected into Config")
+		}
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestOnResolve(t *testing.T) {
+	c := New()
+	c.Add(func(cfg Config) DBService {
+		return &SQLDBService{
+			Config: cfg,
+		}
+	})
+
+	var resolved []reflect.Type
+	var errs []error
+	c.OnResolve(func(t reflect.Type, took time.Duration, err error) {
+		resolved = append(resolved, t)
+		errs = append(errs, err)
+	})
+
+	if err := c.Invoke(func(db DBService) {}); err == nil {
+		t.Error("missing Config provider was not reported")
+	}
+
+	expected := []reflect.Type{reflect.TypeFor[Config](), reflect.TypeFor[DBService]()}
+	if !reflect.DeepEqual(resolved, expected) {
+		t.Errorf("unexpected resolved types %v", resolved)
+	}
+	if errs[0] == nil || errs[1] == nil {
+		t.Error("resolution errors were not passed to the hook")
+	}
+}
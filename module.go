@@ -0,0 +1,238 @@
+package cosmo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// DegradedModule describes a non-critical module that failed during Warmup.
+type DegradedModule struct {
+	Name string
+	// Err is the error returned when building the singletons of the module.
+	Err error
+	// Fallbacks holds the types of the module now built by their fallback.
+	Fallbacks []reflect.Type
+	// Disabled holds the types of the module without a fallback, which are no
+	// longer provided.
+	Disabled []reflect.Type
+}
+
+// NonCritical registers, with register, the providers of an optional module. If
+// building the singletons of the module fails during Warmup, the application still
+// starts: the types of the module are built by the constructors registered with
+// Fallback, or are no longer provided, and the failure is reported by Degraded.
+//
+//	c.NonCritical("recommendations", func(c *cosmo.Container) error {
+//		return c.AddSingleton(NewRecommendationsClient)
+//	})
+//	c.Fallback(NewNoRecommendations)
+//	err := c.Warmup(ctx)
+func (c *Container) NonCritical(module string, register func(c *Container) error) error {
+	c.module = module
+	defer func() { c.module = "" }()
+
+	if err := register(c); err != nil {
+		return fmt.Errorf("module %q: %w", module, err)
+	}
+	return nil
+}
+
+// Fallback registers the constructor used to build its type when the non-critical
+// module providing the type fails during Warmup. Fallbacks are singletons.
+func (c *Container) Fallback(constructor any) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	t, v, err := spec(constructor)
+	if err != nil {
+		return err
+	}
+	c.fallbacks[t] = Spec{
+		Type:  t,
+		Value: v,
+		Scope: ScopeSingleton,
+		order: c.registrations.Add(1),
+	}
+	return nil
+}
+
+// Warmup builds every singleton of the container, in registration order. It returns
+// the first error of a critical provider, while failures of non-critical modules
+// degrade the module and are reported by Degraded. Warmup must be called before the
+// container is used concurrently.
+func (c *Container) Warmup(ctx context.Context) error {
+	var providers []Spec
+	for _, t := range c.providedTypes() {
+		if provider, ok := c.provider(t); ok {
+			providers = append(providers, provider)
+		}
+	}
+	for _, provider := range c.named {
+		providers = append(providers, provider)
+	}
+	for _, g := range c.groups {
+		providers = append(providers, g.specs...)
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].order < providers[j].order
+	})
+
+	for _, provider := range providers {
+		if provider.Scope != ScopeSingleton {
+			continue
+		}
+		for {
+			err := c.warm(ctx, provider)
+			if err == nil {
+				break
+			}
+			module := c.failedModule(err)
+			if module == "" {
+				return err
+			}
+			c.degrade(module, err)
+			if provider.module == module {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// warm builds the singleton of the provider.
+func (c *Container) warm(ctx context.Context, provider Spec) error {
+	var err error
+	switch {
+	case provider.Name != "":
+		_, err = c.resolveNamed(ctx, provider.Name)
+	case provider.Group != "":
+		_, err = c.resolveGroup(ctx, provider.Group)
+	default:
+		if current, ok := c.provider(provider.Type); !ok || current.order != provider.order {
+			// The provider was replaced or removed by a degraded module.
+			return nil
+		}
+		_, err = c.resolve(ctx, provider.Type)
+	}
+	return err
+}
+
+// failedModule returns the non-critical module of the provider whose failure caused
+// err, or an empty string if it's a critical provider or a degraded module.
+func (c *Container) failedModule(err error) string {
+	var failed reflect.Type
+	var (
+		constructorErr *ConstructorError
+		panicErr       *PanicError
+		noProviderErr  *NoProviderError
+	)
+	switch {
+	case errors.As(err, &constructorErr):
+		failed = constructorErr.Type
+	case errors.As(err, &panicErr):
+		failed = panicErr.Type
+	case errors.As(err, &noProviderErr) && len(noProviderErr.Chain) > 0:
+		failed = noProviderErr.Chain[len(noProviderErr.Chain)-1]
+	default:
+		return ""
+	}
+
+	if provider, ok := c.providers[failed]; ok && provider.module != "" && !c.isDegraded(provider.module) {
+		return provider.module
+	}
+
+	for _, provider := range c.named {
+		if provider.Type == failed && provider.module != "" && !c.isDegraded(provider.module) {
+			return provider.module
+		}
+	}
+	for _, g := range c.groups {
+		if g.typ != failed {
+			continue
+		}
+		for _, provider := range g.specs {
+			if provider.module != "" && !c.isDegraded(provider.module) {
+				return provider.module
+			}
+		}
+	}
+
+	return ""
+}
+
+// degrade replaces the type providers of the module with their fallbacks, removes
+// the ones without a fallback and its named providers and group members.
+func (c *Container) degrade(module string, err error) {
+	c.own()
+	degraded := DegradedModule{Name: module, Err: err}
+
+	for t, provider := range c.providers {
+		if provider.module != module {
+			continue
+		}
+		if fallback, ok := c.fallbacks[t]; ok {
+			c.providers[t] = fallback
+			degraded.Fallbacks = append(degraded.Fallbacks, t)
+		} else {
+			delete(c.providers, t)
+			degraded.Disabled = append(degraded.Disabled, t)
+		}
+		c.instances().delete(t)
+	}
+
+	for name, provider := range c.named {
+		if provider.module == module {
+			delete(c.named, name)
+			c.instances().deleteNamed(name)
+		}
+	}
+
+	for name, g := range c.groups {
+		var specs []Spec
+		for _, provider := range g.specs {
+			if provider.module != module {
+				specs = append(specs, provider)
+			}
+		}
+		if len(specs) != len(g.specs) {
+			g.specs = specs
+			c.instances().disposeGroup(name)
+		}
+	}
+
+	sortTypes(degraded.Fallbacks)
+	sortTypes(degraded.Disabled)
+
+	c.mu.Lock()
+	c.degraded = append(c.degraded, degraded)
+	c.mu.Unlock()
+}
+
+func (c *Container) isDegraded(module string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, d := range c.degraded {
+		if d.Name == module {
+			return true
+		}
+	}
+	return false
+}
+
+// Degraded returns the non-critical modules that failed during Warmup, so they can
+// be reported by health checks.
+func (c *Container) Degraded() []DegradedModule {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]DegradedModule(nil), c.degraded...)
+}
+
+func sortTypes(types []reflect.Type) {
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].String() < types[j].String()
+	})
+}
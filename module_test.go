@@ -0,0 +1,72 @@
+package cosmo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type Recommender interface {
+	Recommend() []string
+}
+
+type remoteRecommender struct{}
+
+func (remoteRecommender) Recommend() []string { return []string{"remote"} }
+
+type noRecommender struct{}
+
+func (noRecommender) Recommend() []string { return nil }
+
+func TestWarmupNonCritical(t *testing.T) {
+	c := New()
+	unavailable := errors.New("recommendations service unavailable")
+
+	c.AddSingleton(func() Config { return Config{URL: DBURL} })
+	err := c.NonCritical("recommendations", func(c *Container) error {
+		c.AddSingleton(func() (Recommender, error) { return nil, unavailable })
+		return c.AddSingleton(func(r Recommender) *Session { return &Session{} })
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	c.Fallback(func() Recommender { return noRecommender{} })
+	c.AddSingleton(func(r Recommender, cfg Config) Handler { return Handler{} })
+
+	if err := c.Warmup(context.Background()); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	c.Invoke(func(r Recommender) {
+		if _, ok := r.(noRecommender); !ok {
+			t.Error("failed module type was not resolved by its fallback")
+		}
+	})
+
+	degraded := c.Degraded()
+	if len(degraded) != 1 || degraded[0].Name != "recommendations" || !errors.Is(degraded[0].Err, unavailable) {
+		t.Fatalf("unexpected degraded modules: %+v", degraded)
+	}
+	if !reflect.DeepEqual(degraded[0].Disabled, []reflect.Type{reflect.TypeFor[*Session]()}) {
+		t.Errorf("unexpected disabled types: %v", degraded[0].Disabled)
+	}
+	if c.HasProvider(reflect.TypeFor[*Session]()) {
+		t.Error("type without fallback is still provided")
+	}
+}
+
+func TestWarmupCritical(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() (Config, error) { return Config{}, errors.New("no config") })
+	c.NonCritical("recommendations", func(c *Container) error {
+		return c.AddSingleton(func() Recommender { return remoteRecommender{} })
+	})
+
+	if err := c.Warmup(context.Background()); err == nil {
+		t.Error("warmup ignored the failure of a critical provider")
+	}
+	if len(c.Degraded()) != 0 {
+		t.Error("healthy module was degraded")
+	}
+}
@@ -0,0 +1,41 @@
+package cosmo
+
+// MustAdd is like Container.Add but panics if the constructor is invalid. It's meant
+// for bootstrap code, where an invalid constructor is a programming error.
+func (c *Container) MustAdd(constructor any) {
+	if err := c.Add(constructor); err != nil {
+		panic(err)
+	}
+}
+
+// MustAddSingleton is like Container.AddSingleton but panics if the constructor is
+// invalid.
+func (c *Container) MustAddSingleton(constructor any) {
+	if err := c.AddSingleton(constructor); err != nil {
+		panic(err)
+	}
+}
+
+// MustInvoke is like Container.Invoke but panics if the function can't be invoked or
+// returns an error.
+func (c *Container) MustInvoke(fn any) {
+	if err := c.Invoke(fn); err != nil {
+		panic(err)
+	}
+}
+
+// MustGet is like Container.Get but panics if there is no configuration for key, or
+// if it can't be resolved.
+func (c *Container) MustGet(key string) any {
+	t, ok := c.configuration(key)
+	if !ok {
+		panic(&NoProviderError{Name: key})
+	}
+
+	v, err := c.resolve(c.Context(), t)
+	if err != nil {
+		panic(err)
+	}
+
+	return v.Interface()
+}
@@ -0,0 +1,52 @@
+package cosmo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMustAdd(t *testing.T) {
+	c := New()
+	c.MustAdd(func() Config { return Config{URL: DBURL} })
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("invalid constructor did not panic")
+		}
+	}()
+	c.MustAdd("not a constructor")
+}
+
+func TestMustInvoke(t *testing.T) {
+	c := New()
+	c.MustAddSingleton(func() Config { return Config{URL: DBURL} })
+	c.MustInvoke(func(cfg Config) {
+		if cfg.URL != DBURL {
+			t.Error("wrong config")
+		}
+	})
+
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, ErrNoProvider) {
+			t.Errorf("missing dependency panicked with %v", err)
+		}
+	}()
+	c.MustInvoke(func(DBService) {})
+}
+
+func TestMustGet(t *testing.T) {
+	c := New()
+	c.Configure("config", func() Config { return Config{URL: DBURL} })
+	if cfg := c.MustGet("config").(Config); cfg.URL != DBURL {
+		t.Error("wrong config")
+	}
+
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, ErrNoProvider) {
+			t.Errorf("unknown key panicked with %v", err)
+		}
+	}()
+	c.MustGet("unknown")
+}
@@ -0,0 +1,76 @@
+package cosmo
+
+import (
+	"context"
+	"reflect"
+)
+
+// AddNamedWithScope adds the constructor to the container under name, using the
+// specified scope. Named providers allow many providers of the same type to be
+// registered, and are resolved by name instead of by type.
+func (c *Container) AddNamedWithScope(scope Scope, name string, constructor any) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	if err := checkScope(scope); err != nil {
+		return err
+	}
+	t, v, err := spec(constructor)
+	if err != nil {
+		return err
+	}
+	c.named[name] = Spec{
+		Type:   t,
+		Value:  v,
+		Scope:  scope,
+		Name:   name,
+		order:  c.registrations.Add(1),
+		module: c.module,
+	}
+	c.instances().deleteNamed(name)
+	return nil
+}
+
+// AddNamed adds the constructor to the container under name with ScopeTransient.
+func (c *Container) AddNamed(name string, constructor any) error {
+	if err := c.AddNamedWithScope(ScopeTransient, name, constructor); err != nil {
+		return err
+	}
+	return nil
+}
+
+// hasNamed reports whether there is a named provider or a configuration for name.
+func (c *Container) hasNamed(name string) bool {
+	if _, ok := c.named[name]; ok {
+		return true
+	}
+	if _, ok := c.configurations[name]; ok {
+		return true
+	}
+	return c.parent != nil && c.parent.hasNamed(name)
+}
+
+// resolveNamed returns the instance of the provider registered with name. Keys of
+// configurations registered with Configure are valid names too.
+func (c *Container) resolveNamed(ctx context.Context, name string) (reflect.Value, error) {
+	if inst, ok := c.cacheOf(ctx).getNamed(name); ok {
+		c.metrics.CacheHit(inst.Type())
+		return inst, nil
+	}
+
+	provider, ok := c.named[name]
+	if !ok {
+		if t, ok := c.configurations[name]; ok {
+			return c.resolve(ctx, t)
+		}
+		if c.parent != nil {
+			return c.parent.resolveNamed(ctx, name)
+		}
+		return reflect.Value{}, &NoProviderError{Name: name, Chain: Chain(ctx)}
+	}
+
+	if provider.Scope == ScopeSingleton {
+		return c.buildOnce(ctx, namedSlot(name), namedSlot(name), provider)
+	}
+	return c.call(ctx, provider)
+}
@@ -0,0 +1,64 @@
+package cosmo
+
+import (
+	"context"
+	"reflect"
+)
+
+// Optional wraps a dependency that may not be registered in the container. When
+// there is no provider for T, the container injects the zero value of T with Ok
+// set to false instead of failing the resolution.
+//
+//	c.Invoke(func(tracer cosmo.Optional[Tracer]) {
+//		if tracer.Ok {
+//			tracer.Value.Start()
+//		}
+//	})
+type Optional[T any] struct {
+	Value T
+	Ok    bool
+}
+
+// Get returns the wrapped value and whether it was resolved.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Ok
+}
+
+func (o Optional[T]) optionalElem() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+func (o *Optional[T]) setOptional(v reflect.Value) {
+	o.Value, _ = v.Interface().(T)
+	o.Ok = true
+}
+
+// optional is implemented by *Optional[T], it's used to identify optional dependencies
+// while resolving types.
+type optional interface {
+	optionalElem() reflect.Type
+	setOptional(v reflect.Value)
+}
+
+var optionalType = reflect.TypeFor[optional]()
+
+// resolveOptional resolves the type wrapped by an Optional. If the wrapped type
+// has no provider, the zero value of the Optional is returned.
+func (c *Container) resolveOptional(ctx context.Context, t reflect.Type) (reflect.Value, error) {
+	ptr := reflect.New(t)
+	opt := ptr.Interface().(optional)
+
+	elem := opt.optionalElem()
+	if !c.HasProvider(elem) {
+		return ptr.Elem(), nil
+	}
+
+	v, err := c.resolve(ctx, elem)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	opt.setOptional(v)
+
+	return ptr.Elem(), nil
+}
@@ -0,0 +1,65 @@
+package cosmo
+
+import "testing"
+
+type ToBindOptional struct {
+	DB     Optional[DBService]
+	Config Optional[Config]
+}
+
+func TestOptional(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() Config {
+		return Config{
+			URL: DBURL,
+		}
+	})
+
+	err := c.Invoke(func(cfg Optional[Config], db Optional[DBService]) {
+		if value, ok := cfg.Get(); !ok || value.URL != DBURL {
+			t.Error("wrong value injected into Optional[Config]")
+		}
+		if db.Ok || db.Value != nil {
+			t.Error("missing provider injected into Optional[DBService]")
+		}
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	var bnd ToBindOptional
+	if err = c.Bind(&bnd); err != nil {
+		t.Error(err.Error())
+	}
+	if !bnd.Config.Ok || bnd.DB.Ok {
+		t.Error("wrong values bound to optional fields")
+	}
+}
+
+func TestOptionalMissingTransitiveDependency(t *testing.T) {
+	c := New()
+	c.Add(func(cfg Config) DBService {
+		return &SQLDBService{
+			Config: cfg,
+		}
+	})
+
+	err := c.Invoke(func(db Optional[DBService]) {})
+	if err == nil {
+		t.Error("missing dependency of an optional provider was ignored")
+	}
+}
+
+func TestOptionalNilInterface(t *testing.T) {
+	c := New()
+	c.Add(func() DBService { return nil })
+
+	err := c.Invoke(func(db Optional[DBService]) {
+		if !db.Ok || db.Value != nil {
+			t.Error("nil value was not injected into Optional[DBService]")
+		}
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
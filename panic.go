@@ -0,0 +1,69 @@
+package cosmo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+)
+
+// PanicPolicy defines what the container does when a constructor panics.
+type PanicPolicy int
+
+const (
+	// PanicRecover recovers the panic and returns it as a *PanicError.
+	PanicRecover PanicPolicy = iota
+	// PanicFailFast lets the panic propagate to the caller.
+	PanicFailFast
+)
+
+// SetPanicPolicy sets what the container does when a constructor or a decorator
+// panics. The default is PanicRecover.
+func (c *Container) SetPanicPolicy(policy PanicPolicy) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	c.panicPolicy = policy
+	return nil
+}
+
+// PanicError is returned when a constructor panics and the container uses PanicRecover.
+type PanicError struct {
+	// Type is the type being built by the constructor that panicked.
+	Type reflect.Type
+	// Chain holds the types being resolved when the constructor panicked, the outermost first.
+	Chain []reflect.Type
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the goroutine when the panic was recovered.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("constructor of %v panicked: %v (resolving %s)", e.Type, e.Value, formatChain(e.Chain))
+}
+
+// Unwrap returns the value passed to panic, if it's an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// construct calls the constructor fn of type t, recovering panics according to the
+// container policy.
+func (c *Container) construct(ctx context.Context, t reflect.Type, fn reflect.Value, args []reflect.Value) (out []reflect.Value, err error) {
+	if c.panicPolicy == PanicRecover {
+		defer func() {
+			if r := recover(); r != nil {
+				err = &PanicError{
+					Type:  t,
+					Chain: Chain(ctx),
+					Value: r,
+					Stack: debug.Stack(),
+				}
+			}
+		}()
+	}
+
+	return callFunc(fn, args), nil
+}
@@ -0,0 +1,55 @@
+package cosmo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestConstructorPanic(t *testing.T) {
+	c := New()
+	c.Add(func() Config {
+		panic("config file not found")
+	})
+	c.Add(func(cfg Config) DBService {
+		return &SQLDBService{
+			Config: cfg,
+		}
+	})
+
+	err := c.Invoke(func(db DBService) {})
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected *PanicError, got %v", err)
+	}
+
+	if panicErr.Type != reflect.TypeFor[Config]() || panicErr.Value != "config file not found" {
+		t.Errorf("wrong panic error %v", panicErr)
+	}
+
+	expected := []reflect.Type{reflect.TypeFor[DBService](), reflect.TypeFor[Config]()}
+	if !reflect.DeepEqual(panicErr.Chain, expected) {
+		t.Errorf("wrong resolution chain %v", panicErr.Chain)
+	}
+
+	if err.Error() != "constructor of cosmo.Config panicked: config file not found (resolving cosmo.DBService -> cosmo.Config)" {
+		t.Errorf("unexpected error message %q", err.Error())
+	}
+}
+
+func TestConstructorPanicFailFast(t *testing.T) {
+	c := New()
+	c.SetPanicPolicy(PanicFailFast)
+	c.Add(func() Config {
+		panic("config file not found")
+	})
+
+	defer func() {
+		if r := recover(); r != "config file not found" {
+			t.Errorf("panic was not propagated, got %v", r)
+		}
+	}()
+
+	c.Invoke(func(cfg Config) {})
+}
@@ -0,0 +1,216 @@
+package cosmo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+)
+
+// WarmupParallel builds the singletons of the container like Warmup, but constructs
+// the singletons provided by type concurrently, with up to workers constructors
+// running at a time. A singleton is only constructed once the singletons it depends
+// on, directly or through transient providers, are built, so startups dominated by
+// independent slow constructors, like network clients dialing, are faster:
+//
+//	err := c.WarmupParallel(ctx, 8)
+//
+// Named singletons and group members are built first, sequentially. If a provider
+// of a non-critical module fails, the remaining singletons are built by Warmup,
+// which degrades the module. Like Warmup, it must be called before the container
+// is used concurrently.
+func (c *Container) WarmupParallel(ctx context.Context, workers int) error {
+	if workers < 1 {
+		workers = 1
+	}
+
+	for _, provider := range c.named {
+		if provider.Scope == ScopeSingleton {
+			if err := c.warm(ctx, provider); err != nil {
+				return c.warmupFallback(ctx, err)
+			}
+		}
+	}
+	for _, g := range c.groups {
+		for _, provider := range g.specs {
+			if provider.Scope == ScopeSingleton {
+				if err := c.warm(ctx, provider); err != nil {
+					return c.warmupFallback(ctx, err)
+				}
+			}
+		}
+	}
+
+	nodes := c.singletonNodes()
+	remaining := make(map[reflect.Type]int, len(nodes))
+	dependents := make(map[reflect.Type][]reflect.Type)
+	for t, deps := range nodes {
+		remaining[t] = len(deps)
+		for _, dep := range deps {
+			dependents[dep] = append(dependents[dep], t)
+		}
+	}
+
+	type result struct {
+		typ reflect.Type
+		err error
+	}
+	sem := make(chan struct{}, workers)
+	results := make(chan result)
+	running := 0
+	start := func(t reflect.Type) {
+		running++
+		go func() {
+			sem <- struct{}{}
+			_, err := c.resolve(ctx, t)
+			<-sem
+			results <- result{typ: t, err: err}
+		}()
+	}
+
+	var ready []reflect.Type
+	for t, n := range remaining {
+		if n == 0 {
+			ready = append(ready, t)
+		}
+	}
+	c.sortByOrder(ready)
+	for _, t := range ready {
+		start(t)
+	}
+
+	var errs []error
+	built := 0
+	for running > 0 {
+		r := <-results
+		running--
+		if r.err != nil {
+			errs = append(errs, r.err)
+			continue
+		}
+		built++
+		// Once a constructor fails, the running ones finish but no more are started.
+		if len(errs) > 0 {
+			continue
+		}
+		for _, t := range dependents[r.typ] {
+			if remaining[t]--; remaining[t] == 0 {
+				start(t)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return c.warmupFallback(ctx, errors.Join(errs...))
+	}
+	if built < len(nodes) {
+		// The singletons left depend on each other, Warmup reports the cycle.
+		return c.Warmup(ctx)
+	}
+	return nil
+}
+
+// warmupFallback returns err, unless it was caused by a non-critical module, in
+// which case Warmup builds the singletons and degrades the failed modules.
+func (c *Container) warmupFallback(ctx context.Context, err error) error {
+	var joined interface{ Unwrap() []error }
+	errs := []error{err}
+	if errors.As(err, &joined) {
+		errs = joined.Unwrap()
+	}
+	for _, err := range errs {
+		if c.failedModule(err) == "" {
+			return err
+		}
+	}
+	return c.Warmup(ctx)
+}
+
+// singletonNodes returns the singletons provided by type that aren't built yet,
+// with the singletons they depend on. Of the types built by the same constructor,
+// only one is returned, and the others are replaced by it in the dependencies,
+// since building it caches them too.
+func (c *Container) singletonNodes() map[reflect.Type][]reflect.Type {
+	cache := c.instances()
+	representative := make(map[reflect.Type]reflect.Type)
+	constructors := make(map[reflect.Value]reflect.Type)
+
+	for _, t := range c.providedTypes() {
+		provider, ok := c.provider(t)
+		if !ok || provider.Scope != ScopeSingleton {
+			continue
+		}
+		if _, ok := cache.get(t); ok {
+			continue
+		}
+		rep := t
+		if provider.Value.Type().NumOut() > 1 {
+			if other, ok := constructors[provider.Value]; ok {
+				rep = other
+			} else {
+				constructors[provider.Value] = t
+			}
+		}
+		representative[t] = rep
+	}
+
+	nodes := make(map[reflect.Type][]reflect.Type)
+	for t, rep := range representative {
+		if rep != t {
+			continue
+		}
+		provider, _ := c.provider(t)
+		seen := map[reflect.Type]bool{t: true}
+		var deps []reflect.Type
+		c.walkSingletonDeps(provider, representative, seen, &deps)
+		nodes[t] = deps
+	}
+	return nodes
+}
+
+// walkSingletonDeps adds to deps the singletons the provider depends on, walking
+// through the dependencies of its transient dependencies.
+func (c *Container) walkSingletonDeps(provider Spec, representative map[reflect.Type]reflect.Type, seen map[reflect.Type]bool, deps *[]reflect.Type) {
+	for _, dep := range c.dependencies(provider) {
+		dep = c.adaptedSource(dep)
+		if rep, ok := representative[dep]; ok {
+			dep = rep
+		}
+		if seen[dep] {
+			continue
+		}
+		seen[dep] = true
+
+		if _, ok := representative[dep]; ok {
+			*deps = append(*deps, dep)
+			continue
+		}
+		if next, ok := c.provider(dep); ok && next.Scope != ScopeSingleton {
+			c.walkSingletonDeps(next, representative, seen, deps)
+		}
+	}
+}
+
+// adaptedSource returns the type whose provider builds t: t itself, or the end of
+// its chain of aliases and pointer adaptations.
+func (c *Container) adaptedSource(t reflect.Type) reflect.Type {
+	for {
+		if _, ok := c.provider(t); ok {
+			return t
+		}
+		from, ok := c.adaptation(t)
+		if !ok {
+			return t
+		}
+		t = from
+	}
+}
+
+// sortByOrder sorts the types by the registration order of their providers.
+func (c *Container) sortByOrder(types []reflect.Type) {
+	sort.Slice(types, func(i, j int) bool {
+		a, _ := c.provider(types[i])
+		b, _ := c.provider(types[j])
+		return a.order < b.order
+	})
+}
@@ -0,0 +1,125 @@
+package cosmo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type clientA struct{}
+type clientB struct{}
+type clientC struct{}
+type gateway struct{}
+
+func TestWarmupParallel(t *testing.T) {
+	c := New()
+	var running, maxRunning atomic.Int32
+	dial := func() {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			max := maxRunning.Load()
+			if n <= max || maxRunning.CompareAndSwap(max, n) {
+				break
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	c.AddSingleton(func() Config { return Config{URL: DBURL} })
+	c.AddSingleton(func(Config) *clientA { dial(); return &clientA{} })
+	c.AddSingleton(func(Config) *clientB { dial(); return &clientB{} })
+	c.AddSingleton(func(Config) *clientC { dial(); return &clientC{} })
+	// The gateway depends on the clients through a transient provider.
+	c.Add(func(a *clientA, b *clientB, c *clientC) DBService { return &SQLDBService{} })
+	c.AddSingleton(func(db DBService) *gateway {
+		if running.Load() != 0 {
+			t.Error("singleton was built before its dependencies")
+		}
+		return &gateway{}
+	})
+
+	started := time.Now()
+	if err := c.WarmupParallel(context.Background(), 3); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if maxRunning.Load() != 3 {
+		t.Errorf("%d constructors ran concurrently, expected 3", maxRunning.Load())
+	}
+	if elapsed := time.Since(started); elapsed > 250*time.Millisecond {
+		t.Errorf("independent singletons were not built concurrently, took %v", elapsed)
+	}
+	for _, p := range c.Providers() {
+		if p.Scope == ScopeSingleton && !p.Materialized {
+			t.Errorf("singleton %v was not built", p.Type)
+		}
+	}
+}
+
+func TestWarmupParallelWorkers(t *testing.T) {
+	c := New()
+	var running, maxRunning atomic.Int32
+	dial := func() {
+		if n := running.Add(1); n > maxRunning.Load() {
+			maxRunning.Store(n)
+		}
+		time.Sleep(10 * time.Millisecond)
+		running.Add(-1)
+	}
+	c.AddSingleton(func() *clientA { dial(); return &clientA{} })
+	c.AddSingleton(func() *clientB { dial(); return &clientB{} })
+	c.AddSingleton(func() *clientC { dial(); return &clientC{} })
+
+	if err := c.WarmupParallel(context.Background(), 1); err != nil {
+		t.Fatal(err.Error())
+	}
+	if maxRunning.Load() != 1 {
+		t.Error("more constructors than workers ran concurrently")
+	}
+}
+
+func TestWarmupParallelError(t *testing.T) {
+	c := New()
+	built := false
+	c.AddSingleton(func() (*clientA, error) { return nil, errors.New("dial failed") })
+	c.AddSingleton(func(*clientA) *gateway { built = true; return &gateway{} })
+
+	if err := c.WarmupParallel(context.Background(), 4); err == nil {
+		t.Error("constructor error was not returned")
+	}
+	if built {
+		t.Error("dependent of a failed singleton was built")
+	}
+}
+
+type dialer interface{ dial() }
+
+func (*clientA) dial() {}
+
+func TestWarmupParallelAliasedDependency(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() *clientA { return &clientA{} })
+	c.AddSingleton(func() clientB { return clientB{} })
+	c.Alias(reflect.TypeFor[dialer](), reflect.TypeFor[*clientA]())
+	c.SetPointerAdaptation(true)
+	c.AddSingleton(func(dialer, *clientB) *gateway { return &gateway{} })
+
+	deps := c.singletonNodes()[reflect.TypeFor[*gateway]()]
+	want := map[reflect.Type]bool{reflect.TypeFor[*clientA](): true, reflect.TypeFor[clientB](): true}
+	if len(deps) != len(want) {
+		t.Fatalf("wrong dependencies %v", deps)
+	}
+	for _, dep := range deps {
+		if !want[dep] {
+			t.Errorf("dependency %v was not mapped to its provider", dep)
+		}
+	}
+
+	if err := c.WarmupParallel(context.Background(), 2); err != nil {
+		t.Fatal(err.Error())
+	}
+}
@@ -0,0 +1,183 @@
+package cosmo
+
+import (
+	"context"
+	"encoding/gob"
+	"io"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// plan holds what's needed to call a function with resolved arguments, computed
+// once per function type instead of on every resolution.
+type plan struct {
+	args         []reflect.Type
+	variadic     bool
+	products     int
+	errIndex     int
+	cleanupIndex int
+}
+
+// plans caches the plan of every function type called by the containers.
+var plans sync.Map // map[reflect.Type]*plan
+
+// imported holds the plans read by ImportPlans, by function type name.
+var imported sync.Map // map[string]planRecord
+
+// planOf returns the plan of the function type fn.
+func planOf(fn reflect.Type) *plan {
+	if p, ok := plans.Load(fn); ok {
+		return p.(*plan)
+	}
+
+	p := &plan{args: make([]reflect.Type, fn.NumIn()), variadic: fn.IsVariadic()}
+	for i := range p.args {
+		p.args[i] = fn.In(i)
+	}
+	if rec, ok := importedPlan(fn); ok {
+		p.products, p.errIndex, p.cleanupIndex = rec.Products, rec.ErrIndex, rec.CleanupIndex
+	} else {
+		p.products, p.errIndex, p.cleanupIndex, _ = results(fn)
+	}
+
+	actual, _ := plans.LoadOrStore(fn, p)
+	return actual.(*plan)
+}
+
+// resolveArgs resolves the arguments of the plan.
+func (c *Container) resolveArgs(ctx context.Context, p *plan) ([]reflect.Value, error) {
+	args := make([]reflect.Value, len(p.args))
+	for i := range p.args {
+		val, err := c.resolveArg(ctx, p, i)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = val
+	}
+	return args, nil
+}
+
+// resolveArg resolves the argument i of the plan. The variadic argument receives
+// the slice provider or the group members of its element type, or stays empty
+// when there are none.
+func (c *Container) resolveArg(ctx context.Context, p *plan, i int) (reflect.Value, error) {
+	t := p.args[i]
+	if p.variadic && i == len(p.args)-1 && !c.HasProvider(t) {
+		return reflect.Zero(t), nil
+	}
+	return c.resolve(ctx, t)
+}
+
+// cacheSiblings caches the values built by a singleton constructor returning many
+// values, other than the one of provider, so the constructor is only called once.
+func (c *Container) cacheSiblings(ctx context.Context, provider Spec, values []reflect.Value) error {
+	for i, v := range values {
+		if i == provider.index {
+			continue
+		}
+
+		sibling, ok := c.provider(provider.Value.Type().Out(i))
+		if !ok || sibling.index != i || sibling.Value.Pointer() != provider.Value.Pointer() {
+			continue
+		}
+
+		v, err := c.decorate(ctx, sibling.Type, v)
+		if err != nil {
+			return err
+		}
+		c.cacheOf(ctx).set(sibling.Type, v)
+	}
+	return nil
+}
+
+// callFunc calls fn with args, passing the last argument as the variadic slice.
+func callFunc(fn reflect.Value, args []reflect.Value) []reflect.Value {
+	if fn.Type().IsVariadic() {
+		return fn.CallSlice(args)
+	}
+	return fn.Call(args)
+}
+
+// planRecord is the exported form of a plan. Function types are identified by their
+// name, which doesn't include the path of the packages, so the shape of the type is
+// kept to discard the records of another type with the same name.
+type planRecord struct {
+	Func         string
+	In           int
+	Out          int
+	Products     int
+	ErrIndex     int
+	CleanupIndex int
+}
+
+// matches reports whether the record can be the plan of fn.
+func (r planRecord) matches(fn reflect.Type) bool {
+	if r.In != fn.NumIn() || r.Out != fn.NumOut() || r.Products < 1 || r.Products > r.Out {
+		return false
+	}
+	return r.hasOut(fn, r.ErrIndex, errorType) && r.hasOut(fn, r.CleanupIndex, cleanupType)
+}
+
+// hasOut reports whether fn returns a value of type t at the index i, after the
+// products, or whether i is -1, meaning the value is not returned.
+func (r planRecord) hasOut(fn reflect.Type, i int, t reflect.Type) bool {
+	return i == -1 || i >= r.Products && i < r.Out && fn.Out(i) == t
+}
+
+// importedPlan returns the imported record of fn, if there is one that matches it.
+func importedPlan(fn reflect.Type) (planRecord, bool) {
+	v, ok := imported.Load(fn.String())
+	if !ok {
+		return planRecord{}, false
+	}
+	rec := v.(planRecord)
+	return rec, rec.matches(fn)
+}
+
+// ExportPlans writes the resolution plans of the function types called so far by
+// every container, so they can be imported with ImportPlans when the application
+// starts, instead of analyzing the constructors again. Plans are usually exported
+// by a test that builds the application container and resolves its services:
+//
+//	f, _ := os.Create("plans.bin")
+//	defer f.Close()
+//	err := cosmo.ExportPlans(f)
+func ExportPlans(w io.Writer) error {
+	var records []planRecord
+	plans.Range(func(k, v any) bool {
+		fn, p := k.(reflect.Type), v.(*plan)
+		records = append(records, planRecord{
+			Func:         fn.String(),
+			In:           fn.NumIn(),
+			Out:          fn.NumOut(),
+			Products:     p.products,
+			ErrIndex:     p.errIndex,
+			CleanupIndex: p.cleanupIndex,
+		})
+		return true
+	})
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Func < records[j].Func
+	})
+	return gob.NewEncoder(w).Encode(records)
+}
+
+// ImportPlans reads the plans written by ExportPlans. They're used the first time
+// each function type is called, records that don't match the function type, like
+// the ones exported by an older build, are ignored.
+//
+//	//go:embed plans.bin
+//	var plans []byte
+//
+//	err := cosmo.ImportPlans(bytes.NewReader(plans))
+func ImportPlans(r io.Reader) error {
+	var records []planRecord
+	if err := gob.NewDecoder(r).Decode(&records); err != nil {
+		return err
+	}
+	for _, rec := range records {
+		imported.Store(rec.Func, rec)
+	}
+	return nil
+}
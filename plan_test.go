@@ -0,0 +1,279 @@
+package cosmo
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestExportPlans(t *testing.T) {
+	c := New()
+	c.Add(func() Config { return Config{URL: DBURL} })
+	c.Add(func(cfg Config) (DBService, error) { return &SQLDBService{Config: cfg}, nil })
+	c.Invoke(func(DBService) {})
+
+	var blob bytes.Buffer
+	if err := ExportPlans(&blob); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	fn := reflect.TypeOf(func(cfg Config) (DBService, error) { return nil, nil })
+	plans.Delete(fn)
+	imported.Clear()
+	defer imported.Clear()
+
+	if err := ImportPlans(&blob); err != nil {
+		t.Fatal(err.Error())
+	}
+	if rec, ok := importedPlan(fn); !ok || rec.ErrIndex != 1 {
+		t.Errorf("plan of %v was not imported", fn)
+	}
+	if p := planOf(fn); len(p.args) != 1 || p.products != 1 || p.errIndex != 1 || p.cleanupIndex != -1 {
+		t.Errorf("wrong imported plan %+v", p)
+	}
+
+	if err := ImportPlans(bytes.NewReader([]byte("not a plan"))); err == nil {
+		t.Error("invalid plans did not return error")
+	}
+}
+
+func TestImportPlansMismatch(t *testing.T) {
+	fn := reflect.TypeOf(func(Config) (DBService, error) { return nil, nil })
+	defer imported.Clear()
+
+	records := []planRecord{
+		{In: 1, Out: 2, Products: 1, ErrIndex: -1, CleanupIndex: 1},
+		{In: 1, Out: 2, Products: 2, ErrIndex: 1, CleanupIndex: -1},
+		{In: 2, Out: 2, Products: 1, ErrIndex: 1, CleanupIndex: -1},
+	}
+	for _, rec := range records {
+		rec.Func = fn.String()
+		imported.Store(rec.Func, rec)
+		plans.Delete(fn)
+
+		if p := planOf(fn); p.products != 1 || p.errIndex != 1 {
+			t.Errorf("record %+v that doesn't match the function type was used", rec)
+		}
+	}
+}
+
+type planA struct{}
+type planB struct{ A *planA }
+type planC struct {
+	A *planA
+	B *planB
+}
+
+func newPlanContainer() *Container {
+	c := New()
+	c.Add(func() *planA { return &planA{} })
+	c.Add(func(a *planA) *planB { return &planB{A: a} })
+	c.Add(func(a *planA, b *planB) *planC { return &planC{A: a, B: b} })
+	return c
+}
+
+func TestPlanOf(t *testing.T) {
+	tests := []struct {
+		name         string
+		fn           any
+		args         int
+		variadic     bool
+		products     int
+		errIndex     int
+		cleanupIndex int
+	}{
+		{"no arguments", func() *planA { return nil }, 0, false, 1, -1, -1},
+		{"arguments", func(*planA, *planB) *planC { return nil }, 2, false, 1, -1, -1},
+		{"variadic", func(...*planA) *planB { return nil }, 1, true, 1, -1, -1},
+		{"error", func() (*planA, error) { return nil, nil }, 0, false, 1, 1, -1},
+		{"cleanup and error", func() (*planA, func(), error) { return nil, nil, nil }, 0, false, 1, 2, 1},
+		{"many products", func() (*planA, *planB, error) { return nil, nil, nil }, 0, false, 2, 2, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn := reflect.TypeOf(tt.fn)
+			p := planOf(fn)
+
+			if len(p.args) != tt.args || p.variadic != tt.variadic {
+				t.Errorf("wrong arguments %v, variadic %v", p.args, p.variadic)
+			}
+			if p.products != tt.products || p.errIndex != tt.errIndex || p.cleanupIndex != tt.cleanupIndex {
+				t.Errorf("wrong results products=%d err=%d cleanup=%d", p.products, p.errIndex, p.cleanupIndex)
+			}
+			if planOf(fn) != p {
+				t.Error("plan was not cached")
+			}
+		})
+	}
+}
+
+func TestResolveLookupOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(c *Container)
+		check    func(t *testing.T, c *Container)
+	}{
+		{
+			name:     "optional without provider",
+			register: func(c *Container) {},
+			check: func(t *testing.T, c *Container) {
+				c.Invoke(func(a Optional[*planA]) {
+					if a.Ok {
+						t.Error("optional without provider was set")
+					}
+				})
+			},
+		},
+		{
+			name: "optional with provider",
+			register: func(c *Container) {
+				c.Add(func() *planA { return &planA{} })
+			},
+			check: func(t *testing.T, c *Container) {
+				c.Invoke(func(a Optional[*planA]) {
+					if !a.Ok || a.Value == nil {
+						t.Error("optional with provider was not set")
+					}
+				})
+			},
+		},
+		{
+			name: "provider of the optional type comes first",
+			register: func(c *Container) {
+				c.Add(func() *planA { return &planA{} })
+				c.Add(func() Optional[*planA] { return Optional[*planA]{} })
+			},
+			check: func(t *testing.T, c *Container) {
+				c.Invoke(func(a Optional[*planA]) {
+					if a.Ok {
+						t.Error("provider of the optional type was not used")
+					}
+				})
+			},
+		},
+		{
+			name: "lazy",
+			register: func(c *Container) {
+				c.Add(func() *planA { return &planA{} })
+			},
+			check: func(t *testing.T, c *Container) {
+				c.Invoke(func(a Lazy[*planA]) {
+					if v, err := a.Get(); err != nil || v == nil {
+						t.Errorf("lazy was not resolved, err %v", err)
+					}
+				})
+			},
+		},
+		{
+			name: "named comes before the type",
+			register: func(c *Container) {
+				c.Add(func() Config { return Config{URL: "type"} })
+				c.AddNamed("primary", func() Config { return Config{URL: "named"} })
+			},
+			check: func(t *testing.T, c *Container) {
+				var deps struct {
+					Config Config `cosmo:"name=primary"`
+				}
+				if err := c.Bind(&deps); err != nil || deps.Config.URL != "named" {
+					t.Errorf("named provider was not used, err %v", err)
+				}
+			},
+		},
+		{
+			name: "group",
+			register: func(c *Container) {
+				c.AddToGroup("as", func() *planA { return &planA{} })
+				c.AddToGroup("as", func() *planA { return &planA{} })
+			},
+			check: func(t *testing.T, c *Container) {
+				c.Invoke(func(as []*planA) {
+					if len(as) != 2 {
+						t.Errorf("group resolved %d members", len(as))
+					}
+				})
+			},
+		},
+		{
+			name: "provider of the slice comes before the group",
+			register: func(c *Container) {
+				c.AddToGroup("as", func() *planA { return &planA{} })
+				c.Add(func() []*planA { return nil })
+			},
+			check: func(t *testing.T, c *Container) {
+				c.Invoke(func(as []*planA) {
+					if len(as) != 0 {
+						t.Error("group was used over the slice provider")
+					}
+				})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			tt.register(c)
+			tt.check(t, c)
+		})
+	}
+}
+
+func TestPlanSharedByClones(t *testing.T) {
+	c := New()
+	c.Add(func() Config { return Config{URL: DBURL} })
+	fn := func(cfg Config) string { return cfg.URL }
+
+	if url, _ := InvokeResult[string](c, fn); url != DBURL {
+		t.Fatalf("wrong url %q", url)
+	}
+	p := planOf(reflect.TypeOf(fn))
+
+	clone := c.Clone()
+	clone.Add(func() Config { return Config{URL: "postgres://clone"} })
+
+	if url, _ := InvokeResult[string](clone, fn); url != "postgres://clone" {
+		t.Errorf("clone resolved %q with the cached plan", url)
+	}
+	if url, _ := InvokeResult[string](c, fn); url != DBURL {
+		t.Errorf("container resolved %q after its clone changed", url)
+	}
+	if planOf(reflect.TypeOf(fn)) != p {
+		t.Error("plan was not shared by the clone")
+	}
+}
+
+func BenchmarkResolveTransient(b *testing.B) {
+	c := newPlanContainer()
+	fn := func(*planC) {}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := c.Invoke(fn); err != nil {
+			b.Fatal(err.Error())
+		}
+	}
+}
+
+func BenchmarkResolveSingleton(b *testing.B) {
+	c := New()
+	c.AddSingleton(func() *planA { return &planA{} })
+	fn := func(*planA) {}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := c.Invoke(fn); err != nil {
+			b.Fatal(err.Error())
+		}
+	}
+}
+
+func BenchmarkPlanOf(b *testing.B) {
+	fn := reflect.TypeOf(func(Config, DBService) (DBService, error) { return nil, nil })
+
+	b.ReportAllocs()
+	for b.Loop() {
+		plans.Delete(fn)
+		planOf(fn)
+	}
+}
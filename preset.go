@@ -0,0 +1,69 @@
+package cosmo
+
+import "fmt"
+
+// Preset is a named set of registrations that can inherit the registrations of
+// other presets. It replaces if/else composition roots for multi-environment
+// services:
+//
+//	base := cosmo.NewPreset("base", func(c *cosmo.Container) error {
+//		c.AddSingleton(NewLogger)
+//		return c.AddSingleton(NewPostgresDB)
+//	})
+//	dev := base.Extend("dev", func(c *cosmo.Container) error {
+//		return c.AddSingleton(NewSQLiteDB)
+//	})
+//	err := dev.Apply(c)
+type Preset struct {
+	Name     string
+	Parents  []*Preset
+	Register func(c *Container) error
+}
+
+// NewPreset creates a preset without parents.
+func NewPreset(name string, register func(c *Container) error) *Preset {
+	return &Preset{
+		Name:     name,
+		Register: register,
+	}
+}
+
+// Extend creates a preset that inherits the registrations of p, overriding them
+// with its own.
+func (p *Preset) Extend(name string, register func(c *Container) error) *Preset {
+	return &Preset{
+		Name:     name,
+		Parents:  []*Preset{p},
+		Register: register,
+	}
+}
+
+// Apply registers the parents of the preset, in order, and then the preset own
+// registrations, so a preset overrides the providers of the presets it inherits.
+// A preset inherited more than once is only applied once.
+func (p *Preset) Apply(c *Container) error {
+	return p.apply(c, make(map[*Preset]bool))
+}
+
+func (p *Preset) apply(c *Container, applied map[*Preset]bool) error {
+	if applied[p] {
+		return nil
+	}
+	applied[p] = true
+
+	for _, parent := range p.Parents {
+		if err := parent.apply(c, applied); err != nil {
+			return err
+		}
+	}
+
+	if p.Register == nil {
+		return nil
+	}
+
+	if err := p.Register(c); err != nil {
+		return fmt.Errorf("preset %q: %w", p.Name, err)
+	}
+
+	return nil
+}
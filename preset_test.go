@@ -0,0 +1,57 @@
+package cosmo
+
+import "testing"
+
+func TestPreset(t *testing.T) {
+	var applied []string
+
+	base := NewPreset("base", func(c *Container) error {
+		applied = append(applied, "base")
+		c.AddSingleton(func() Config {
+			return Config{URL: DBURL}
+		})
+		return c.Add(func(cfg Config) DBService {
+			return &SQLDBService{Config: cfg}
+		})
+	})
+	tracing := NewPreset("tracing", func(c *Container) error {
+		applied = append(applied, "tracing")
+		return nil
+	})
+	tracing.Parents = []*Preset{base}
+
+	dev := base.Extend("dev", func(c *Container) error {
+		applied = append(applied, "dev")
+		return c.Add(func() DBService {
+			return &MemoryDBService{}
+		})
+	})
+	dev.Parents = append(dev.Parents, tracing)
+
+	c := New()
+	if err := dev.Apply(c); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(applied) != 3 || applied[0] != "base" || applied[1] != "tracing" || applied[2] != "dev" {
+		t.Errorf("presets applied in the wrong order: %v", applied)
+	}
+
+	err := c.Invoke(func(db DBService, cfg Config) {
+		if _, ok := db.(*MemoryDBService); !ok {
+			t.Error("dev preset did not override base provider")
+		}
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestPresetError(t *testing.T) {
+	broken := NewPreset("broken", func(c *Container) error {
+		return c.Add(Config{})
+	})
+	if err := broken.Extend("dev", nil).Apply(New()); err == nil {
+		t.Error("error of parent preset was not returned")
+	}
+}
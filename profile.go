@@ -0,0 +1,146 @@
+package cosmo
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Condition selects when a provider added with AddWhen is active.
+type Condition struct {
+	// Profiles activates the provider when any of them is active.
+	Profiles []string
+}
+
+// Profile returns a condition that's met when any of the profiles is active.
+func Profile(names ...string) Condition {
+	return Condition{Profiles: names}
+}
+
+// SetProfiles sets the active profiles, replacing the previous ones. Instances
+// cached for the types with conditional providers are disposed, running their
+// cleanup functions, but SetProfiles is meant to be called at startup, before
+// resolving them.
+func (c *Container) SetProfiles(names ...string) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	profiles := make(map[string]bool, len(names))
+	for _, name := range names {
+		profiles[name] = true
+	}
+	c.profiles = profiles
+
+	types := make(map[reflect.Type]bool, len(c.conditional))
+	for t := range c.conditional {
+		types[t] = true
+	}
+	return runCleanups(c.instances().evict(types))
+}
+
+// Profiles returns the active profiles, sorted.
+func (c *Container) Profiles() []string {
+	var names []string
+	for name := range c.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AddWhenWithScope adds the constructor using the specified scope, as a provider
+// that's only used while the condition is met. An active conditional provider takes
+// precedence over the provider added with AddWithScope, so it can replace a default:
+//
+//	c.AddSingleton(NewFakeMailer)
+//	c.AddWhenWithScope(cosmo.ScopeSingleton, cosmo.Profile("prod"), NewRealMailer)
+//	c.SetProfiles(os.Getenv("APP_PROFILE"))
+//
+// When many conditional providers of a type are active, the last one added is used.
+func (c *Container) AddWhenWithScope(scope Scope, cond Condition, constructor any) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	if err := checkScope(scope); err != nil {
+		return err
+	}
+	_, v, err := spec(constructor)
+	if err != nil {
+		return err
+	}
+	if len(cond.Profiles) == 0 {
+		return fmt.Errorf("condition of %v has no profiles", v.Type())
+	}
+
+	types := make(map[reflect.Type]bool)
+	for _, provider := range c.specs(scope, v) {
+		provider.profiles = cond.Profiles
+		c.conditional[provider.Type] = append(c.conditional[provider.Type], provider)
+		types[provider.Type] = true
+	}
+	return runCleanups(c.instances().evict(types))
+}
+
+// AddWhen adds the constructor with ScopeTransient, as a provider that's only used
+// while the condition is met.
+//
+//	c.AddWhen(cosmo.Profile("dev", "test"), NewFakeMailer)
+//	c.AddWhen(cosmo.Profile("prod"), NewRealMailer)
+func (c *Container) AddWhen(cond Condition, constructor any) error {
+	if err := c.AddWhenWithScope(ScopeTransient, cond, constructor); err != nil {
+		return err
+	}
+	return nil
+}
+
+// provider returns the provider of t: the last active conditional provider, or the
+// provider added without condition.
+func (c *Container) provider(t reflect.Type) (Spec, bool) {
+	if conditional := c.conditional[t]; len(conditional) > 0 {
+		for i := len(conditional) - 1; i >= 0; i-- {
+			if c.active(conditional[i]) {
+				return conditional[i], true
+			}
+		}
+	}
+	provider, ok := c.providers[t]
+	return provider, ok
+}
+
+// providedTypes returns the types with a provider, conditional or not.
+func (c *Container) providedTypes() []reflect.Type {
+	types := make([]reflect.Type, 0, len(c.providers)+len(c.conditional))
+	for t := range c.providers {
+		types = append(types, t)
+	}
+	for t := range c.conditional {
+		if _, ok := c.providers[t]; !ok {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+func (c *Container) active(provider Spec) bool {
+	for _, profile := range provider.profiles {
+		if c.profiles[profile] {
+			return true
+		}
+	}
+	return false
+}
+
+// inactiveProfiles returns the profiles of the conditional providers of t.
+func (c *Container) inactiveProfiles(t reflect.Type) []string {
+	var profiles []string
+	seen := make(map[string]bool)
+	for _, provider := range c.conditional[t] {
+		for _, profile := range provider.profiles {
+			if !seen[profile] {
+				seen[profile] = true
+				profiles = append(profiles, profile)
+			}
+		}
+	}
+	return profiles
+}
@@ -0,0 +1,104 @@
+package cosmo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type Mailer interface {
+	Send(to string) error
+}
+
+type fakeMailer struct{}
+
+func (fakeMailer) Send(string) error { return nil }
+
+type smtpMailer struct{}
+
+func (smtpMailer) Send(string) error { return nil }
+
+func TestProfiles(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() Mailer { return fakeMailer{} })
+	if err := c.AddWhen(Profile("prod", "staging"), func() Mailer { return smtpMailer{} }); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	c.Invoke(func(m Mailer) {
+		if _, ok := m.(fakeMailer); !ok {
+			t.Error("default provider was not used without active profiles")
+		}
+	})
+
+	c.SetProfiles("prod")
+	c.Invoke(func(m Mailer) {
+		if _, ok := m.(smtpMailer); !ok {
+			t.Error("conditional provider of the active profile was not used")
+		}
+	})
+}
+
+func TestInactiveProfiles(t *testing.T) {
+	c := New()
+	c.AddWhen(Profile("prod"), func() Mailer { return smtpMailer{} })
+	c.SetProfiles("dev")
+
+	err := c.Invoke(func(m Mailer) {})
+	if !errors.Is(err, ErrNoProvider) {
+		t.Fatalf("expected ErrNoProvider, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "inactive profiles prod") {
+		t.Errorf("error doesn't mention the inactive profiles: %v", err)
+	}
+
+	if err := c.AddWhen(Condition{}, func() Mailer { return smtpMailer{} }); err == nil {
+		t.Error("condition without profiles was accepted")
+	}
+}
+
+func TestConditionalSingletons(t *testing.T) {
+	newContainer := func(built *int) *Container {
+		c := New()
+		c.SetProfiles("prod")
+		c.AddSingleton(func() Config { return Config{URL: DBURL} })
+		c.AddWhenWithScope(ScopeSingleton, Profile("prod"), func(cfg Config) DBService {
+			*built++
+			return &SQLDBService{Config: cfg}
+		})
+		return c
+	}
+
+	warmups := map[string]func(*Container) error{
+		"Warmup":         func(c *Container) error { return c.Warmup(context.Background()) },
+		"WarmupParallel": func(c *Container) error { return c.WarmupParallel(context.Background(), 2) },
+		"Reload":         func(c *Container) error { return c.Reload() },
+	}
+	for name, warmup := range warmups {
+		built := 0
+		if err := warmup(newContainer(&built)); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if built != 1 {
+			t.Errorf("%s built the conditional singleton %d times", name, built)
+		}
+	}
+
+	built := 0
+	c := newContainer(&built)
+	c.Invoke(func(DBService) {})
+	for _, info := range c.Providers() {
+		if info.Type == reflect.TypeFor[DBService]() && !info.Materialized {
+			t.Error("built conditional singleton is not reported as materialized")
+		}
+	}
+
+	if err := c.Rebuild(reflect.TypeFor[Config]()); err != nil {
+		t.Fatal(err.Error())
+	}
+	if built != 2 {
+		t.Errorf("conditional dependent of the rebuilt type was built %d times", built)
+	}
+}
@@ -0,0 +1,107 @@
+package cosmo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ErrNotReady is returned when resolving a singleton whose Gate is not open.
+var ErrNotReady = errors.New("dependency not ready")
+
+// Gate signals that an external system, like a database or a message broker, is
+// ready to be used. It's opened by the code that waits for the system to be up.
+type Gate struct {
+	once sync.Once
+	ch   chan struct{}
+}
+
+// NewGate creates a closed gate.
+func NewGate() *Gate {
+	return &Gate{ch: make(chan struct{})}
+}
+
+// Open opens the gate, releasing the resolutions waiting for it. It's safe to call
+// Open more than once.
+func (g *Gate) Open() {
+	g.once.Do(func() { close(g.ch) })
+}
+
+// Ready returns a channel that's closed when the gate is opened.
+func (g *Gate) Ready() <-chan struct{} {
+	return g.ch
+}
+
+// ReadyPolicy defines how resolutions behave while a Gate is not open.
+type ReadyPolicy struct {
+	// Timeout is how long resolutions wait for the gate before returning ErrNotReady.
+	// When it's zero, resolutions return ErrNotReady right away.
+	Timeout time.Duration
+}
+
+// readiness holds the gate and policy of a provider.
+type readiness struct {
+	gate   *Gate
+	policy ReadyPolicy
+}
+
+// AddSingletonAfter adds the constructor with ScopeSingleton, but the constructor is
+// only called after the gate is open. Until then, resolutions of the type wait or
+// fail with ErrNotReady, according to the policy, and nothing is cached, so a
+// half-initialized instance is never served. Constructors returning many values
+// are registered as the provider of each of them, like with AddSingleton.
+//
+//	brokerUp := cosmo.NewGate()
+//	c.AddSingletonAfter(brokerUp, cosmo.ReadyPolicy{Timeout: 5 * time.Second}, NewPublisher)
+//
+//	go func() {
+//		waitForBroker()
+//		brokerUp.Open()
+//	}()
+func (c *Container) AddSingletonAfter(gate *Gate, policy ReadyPolicy, constructor any) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	if gate == nil {
+		return errors.New("gate of AddSingletonAfter is nil")
+	}
+	_, v, err := spec(constructor)
+	if err != nil {
+		return err
+	}
+
+	ready := &readiness{gate: gate, policy: policy}
+	for _, provider := range c.specs(ScopeSingleton, v) {
+		provider.ready = ready
+		c.providers[provider.Type] = provider
+	}
+	return nil
+}
+
+// wait blocks until the gate is open, the policy timeout expires or ctx is done.
+func (r *readiness) wait(ctx context.Context, t reflect.Type) error {
+	select {
+	case <-r.gate.Ready():
+		return nil
+	default:
+	}
+
+	if r.policy.Timeout <= 0 {
+		return fmt.Errorf("%w: %v", ErrNotReady, t)
+	}
+
+	timer := time.NewTimer(r.policy.Timeout)
+	defer timer.Stop()
+
+	select {
+	case <-r.gate.Ready():
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("%w: %v after %v", ErrNotReady, t, r.policy.Timeout)
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %v: %w", ErrNotReady, t, ctx.Err())
+	}
+}
@@ -0,0 +1,92 @@
+package cosmo
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAddSingletonAfter(t *testing.T) {
+	c := New()
+	gate := NewGate()
+	built := 0
+
+	c.AddSingletonAfter(gate, ReadyPolicy{}, func() DBService {
+		built++
+		return &SQLDBService{}
+	})
+
+	if err := c.Invoke(func(db DBService) {}); !errors.Is(err, ErrNotReady) {
+		t.Errorf("expected ErrNotReady, got %v", err)
+	}
+	if built != 0 {
+		t.Error("constructor was called before the gate was open")
+	}
+
+	gate.Open()
+	gate.Open()
+
+	if err := c.Invoke(func(db DBService) {}); err != nil {
+		t.Error(err.Error())
+	}
+	c.Invoke(func(db DBService) {})
+	if built != 1 {
+		t.Errorf("singleton was built %d times", built)
+	}
+}
+
+func TestAddSingletonAfterTimeout(t *testing.T) {
+	c := New()
+	gate := NewGate()
+	c.AddSingletonAfter(gate, ReadyPolicy{Timeout: 10 * time.Millisecond}, func() DBService {
+		return &SQLDBService{}
+	})
+
+	if err := c.Invoke(func(db DBService) {}); !errors.Is(err, ErrNotReady) {
+		t.Errorf("expected ErrNotReady, got %v", err)
+	}
+
+	c.AddSingletonAfter(gate, ReadyPolicy{Timeout: 5 * time.Second}, func() DBService {
+		return &SQLDBService{}
+	})
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		gate.Open()
+	}()
+
+	if err := c.Invoke(func(db DBService) {}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestAddSingletonAfterManyValues(t *testing.T) {
+	c := New()
+	gate := NewGate()
+	built := 0
+
+	if err := c.AddSingletonAfter(nil, ReadyPolicy{}, func() Config { return Config{} }); err == nil {
+		t.Error("nil gate did not return error")
+	}
+
+	c.AddSingletonAfter(gate, ReadyPolicy{}, func() (Config, DBService) {
+		built++
+		return Config{URL: DBURL}, &SQLDBService{}
+	})
+
+	if err := c.Invoke(func(Config) {}); !errors.Is(err, ErrNotReady) {
+		t.Errorf("expected ErrNotReady, got %v", err)
+	}
+
+	gate.Open()
+	err := c.Invoke(func(cfg Config, db DBService) {
+		if cfg.URL != DBURL || db == nil {
+			t.Error("values of the constructor were not resolved")
+		}
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	if built != 1 {
+		t.Errorf("constructor was called %d times", built)
+	}
+}
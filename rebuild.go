@@ -0,0 +1,157 @@
+package cosmo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+)
+
+// rebuildGate blocks the resolution of the types being rebuilt until done is closed.
+type rebuildGate struct {
+	types map[reflect.Type]bool
+	done  chan struct{}
+}
+
+type rebuildKey struct{}
+
+// Rebuild disposes the singletons of the given types, and the singletons that depend
+// on them, directly or not, and builds them again. It allows recovering from poisoned
+// instances, like a connection pool in a bad state, without restarting the application.
+//
+//	err := c.Rebuild(reflect.TypeFor[*sql.DB]())
+//
+// Types are given as accepted by TypeOf, a nil type returns an error.
+//
+// While the rebuild runs, resolutions of the affected types wait for it to finish,
+// resolutions of other types are not affected.
+func (c *Container) Rebuild(types ...any) error {
+	targets := make([]reflect.Type, 0, len(types))
+	for _, v := range types {
+		t := TypeOf(v)
+		if t == nil {
+			return errors.New("can't rebuild a nil type")
+		}
+		if _, ok := c.provider(t); !ok {
+			return &NoProviderError{Type: t}
+		}
+		targets = append(targets, t)
+	}
+
+	c.rebuildMu.Lock()
+	defer c.rebuildMu.Unlock()
+
+	gate := &rebuildGate{
+		types: c.dependents(targets),
+		done:  make(chan struct{}),
+	}
+	c.rebuilding.Store(gate)
+	defer func() {
+		c.rebuilding.Store(nil)
+		close(gate.done)
+	}()
+
+	disposeErr := runCleanups(c.instances().evict(gate.types))
+
+	ctx := context.WithValue(c.Context(), rebuildKey{}, gate)
+	var buildErr error
+	for t := range gate.types {
+		if provider, _ := c.provider(t); provider.Scope != ScopeSingleton {
+			continue
+		}
+		if _, err := c.resolve(ctx, t); err != nil {
+			buildErr = err
+			break
+		}
+	}
+
+	return errors.Join(disposeErr, buildErr)
+}
+
+// waitRebuild blocks until t is not being rebuilt, unless the resolution is part of
+// the rebuild.
+func (c *Container) waitRebuild(ctx context.Context, t reflect.Type) error {
+	gate := c.rebuilding.Load()
+	if gate == nil || !gate.types[t] || ctx.Value(rebuildKey{}) == gate {
+		return nil
+	}
+
+	select {
+	case <-gate.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// dependents returns the targets and the types whose providers depend on them,
+// directly or not.
+func (c *Container) dependents(targets []reflect.Type) map[reflect.Type]bool {
+	affected := make(map[reflect.Type]bool)
+	for _, t := range targets {
+		affected[t] = true
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for _, t := range c.providedTypes() {
+			provider, ok := c.provider(t)
+			if !ok || affected[t] {
+				continue
+			}
+			for _, dep := range c.dependencies(provider) {
+				if affected[dep] {
+					affected[t] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	return affected
+}
+
+// dependencies returns the types the provider depends on, including the dependencies
+// of the decorators and setters of its type. Optional and lazy dependencies are
+// unwrapped.
+func (c *Container) dependencies(provider Spec) []reflect.Type {
+	var deps []reflect.Type
+
+	add := func(fn reflect.Type, skip reflect.Type) {
+		for i := 0; i < fn.NumIn(); i++ {
+			in := fn.In(i)
+			if in == skip || in == contextType {
+				continue
+			}
+			deps = append(deps, dependencyOf(in))
+		}
+	}
+
+	add(provider.Value.Type(), nil)
+	for _, decorator := range c.decorators[provider.Type] {
+		add(decorator.Type(), provider.Type)
+	}
+	for _, name := range c.setters[provider.Type] {
+		// The receiver of concrete types is skipped like the decorated argument.
+		if m, ok := provider.Type.MethodByName(name); ok {
+			add(m.Type, provider.Type)
+		}
+	}
+
+	return deps
+}
+
+// TypeOf returns v if it's a reflect.Type, or the type of v. Since interfaces have
+// no values of their own, a pointer to an interface, like (*DBService)(nil),
+// returns the interface type. It returns nil for nil.
+func TypeOf(v any) reflect.Type {
+	if t, ok := v.(reflect.Type); ok {
+		return t
+	}
+
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Interface {
+		return t.Elem()
+	}
+	return t
+}
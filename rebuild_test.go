@@ -0,0 +1,102 @@
+package cosmo
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type Handler struct {
+	DB DBService
+}
+
+func TestRebuild(t *testing.T) {
+	c := New()
+	var disposed []string
+	configBuilt, dbBuilt, handlerBuilt := 0, 0, 0
+
+	c.AddSingleton(func() Config {
+		configBuilt++
+		return Config{URL: DBURL}
+	})
+	c.AddSingleton(func(cfg Config) (DBService, func()) {
+		dbBuilt++
+		return &SQLDBService{Config: cfg}, func() { disposed = append(disposed, "db") }
+	})
+	c.AddSingleton(func(db DBService) (*Handler, func()) {
+		handlerBuilt++
+		return &Handler{DB: db}, func() { disposed = append(disposed, "handler") }
+	})
+
+	var before *Handler
+	c.Invoke(func(h *Handler) { before = h })
+
+	if err := c.Rebuild((*DBService)(nil)); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if configBuilt != 1 || dbBuilt != 2 || handlerBuilt != 2 {
+		t.Errorf("wrong rebuild counts config=%d db=%d handler=%d", configBuilt, dbBuilt, handlerBuilt)
+	}
+	if !reflect.DeepEqual(disposed, []string{"handler", "db"}) {
+		t.Errorf("wrong disposal order %v", disposed)
+	}
+
+	c.Invoke(func(h *Handler) {
+		if h == before {
+			t.Error("dependent singleton was not rebuilt")
+		}
+	})
+	if handlerBuilt != 2 {
+		t.Error("rebuilt singleton was not cached")
+	}
+
+	if err := c.Rebuild(reflect.TypeFor[Validator]()); err == nil {
+		t.Error("rebuild of an unknown type did not return error")
+	}
+}
+
+func TestRebuildConcurrentResolution(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() Config {
+		return Config{URL: DBURL}
+	})
+	c.AddSingleton(func(cfg Config) DBService {
+		return &SQLDBService{Config: cfg}
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if err := c.Invoke(func(db DBService) {}); err != nil {
+					t.Error(err.Error())
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 10; i++ {
+		if err := c.Rebuild(Config{}); err != nil {
+			t.Error(err.Error())
+		}
+	}
+	wg.Wait()
+}
+
+func TestRebuildInvalidTypes(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() Config { return Config{URL: DBURL} })
+
+	for name, typ := range map[string]any{
+		"nil":         nil,
+		"nil type":    reflect.Type(nil),
+		"no provider": (*DBService)(nil),
+	} {
+		if err := c.Rebuild(typ); err == nil {
+			t.Errorf("%s: rebuild did not return error", name)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package cosmo
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+)
+
+// Redacted replaces the value of string fields marked as secret.
+const Redacted = "[REDACTED]"
+
+// Redactor returns a copy of a value that's safe to print.
+type Redactor func(v any) any
+
+// AddRedactor registers a redactor for the values of the type of typ, given as
+// accepted by TypeOf. It's used by Sanitize, and by everything in the container that
+// prints values, instead of the default field redaction. A nil type returns an error.
+//
+//	c.AddRedactor((*url.URL)(nil), func(v any) any {
+//		return v.(*url.URL).Redacted()
+//	})
+func (c *Container) AddRedactor(typ any, redactor Redactor) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	t := TypeOf(typ)
+	if t == nil {
+		return errors.New("can't add a redactor for a nil type")
+	}
+	c.redactors[t] = redactor
+	return nil
+}
+
+// RedactField marks a field of the struct type typ, given as accepted by TypeOf, as
+// secret. Fields can also be marked with the `cosmo:"secret"` struct tag. A nil type
+// returns an error.
+func (c *Container) RedactField(typ any, field string) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	t := TypeOf(typ)
+	if t == nil {
+		return errors.New("can't redact a field of a nil type")
+	}
+	if c.secretFields[t] == nil {
+		c.secretFields[t] = make(map[string]bool)
+	}
+	c.secretFields[t][field] = true
+	return nil
+}
+
+// Sanitize returns a copy of v where the values with a redactor are redacted, and the
+// exported secret fields are replaced by Redacted, if they're strings, or by their
+// zero value. Structs, pointers, slices, maps and interfaces are sanitized
+// recursively, so v must not contain pointer cycles. Unexported fields are copied as
+// they are, so secrets must be kept in exported fields to be redacted.
+func (c *Container) Sanitize(v any) any {
+	if v == nil {
+		return nil
+	}
+	return c.sanitize(reflect.ValueOf(v)).Interface()
+}
+
+func (c *Container) sanitize(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+	}
+
+	if redactor, ok := c.redactors[v.Type()]; ok {
+		if v.CanInterface() {
+			// A redactor returning nil redacts the value to its zero value.
+			if out := reflect.ValueOf(redactor(v.Interface())); out.IsValid() {
+				return out
+			}
+			return reflect.Zero(v.Type())
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		out := reflect.New(v.Type().Elem())
+		assign(out.Elem(), c.sanitize(v.Elem()))
+		return out
+	case reflect.Interface:
+		out := reflect.New(v.Type()).Elem()
+		assign(out, c.sanitize(v.Elem()))
+		return out
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if c.isSecret(v.Type(), field) {
+				out.Field(i).Set(redacted(field.Type))
+				continue
+			}
+			assign(out.Field(i), c.sanitize(v.Field(i)))
+		}
+		return out
+	case reflect.Slice:
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			assign(out.Index(i), c.sanitize(v.Index(i)))
+		}
+		return out
+	case reflect.Map:
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			value := reflect.New(v.Type().Elem()).Elem()
+			assign(value, c.sanitize(iter.Value()))
+			out.SetMapIndex(iter.Key(), value)
+		}
+		return out
+	}
+
+	return v
+}
+
+// assign sets dst to v, or to its zero value if a redactor returned a value that can't
+// be assigned to dst.
+func assign(dst reflect.Value, v reflect.Value) {
+	if v.IsValid() && v.Type().AssignableTo(dst.Type()) {
+		dst.Set(v)
+		return
+	}
+	dst.Set(reflect.Zero(dst.Type()))
+}
+
+// isSecret reports whether the field of the struct type t must be redacted.
+func (c *Container) isSecret(t reflect.Type, field reflect.StructField) bool {
+	if c.secretFields[t][field.Name] {
+		return true
+	}
+	tag, err := parseTag(field)
+	return err == nil && tag.secret
+}
+
+// redacted returns the value that replaces a secret field of type t.
+func redacted(t reflect.Type) reflect.Value {
+	if t.Kind() == reflect.String {
+		return reflect.ValueOf(Redacted).Convert(t)
+	}
+	return reflect.Zero(t)
+}
+
+// Dump writes the singletons built by the container, sanitized, one per line and
+// sorted by type. It's meant for debug endpoints and startup logs.
+func (c *Container) Dump(w io.Writer) error {
+	instances := c.instances().snapshot()
+
+	types := make([]reflect.Type, 0, len(instances))
+	for t := range instances {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].String() < types[j].String()
+	})
+
+	for _, t := range types {
+		if _, err := fmt.Fprintf(w, "%v: %+v\n", t, c.Sanitize(instances[t].Interface())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
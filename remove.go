@@ -0,0 +1,49 @@
+package cosmo
+
+import "reflect"
+
+// Remove unregisters the providers of t, including the ones added with AddWhen, and
+// disposes its cached singleton, running its cleanup function. It allows hot-swapping
+// a provider, like a plugin being reloaded, in long-running applications:
+//
+//	c.Remove(reflect.TypeFor[Plugin]())
+//	c.AddSingleton(NewPlugin)
+//
+// Instances that already depend on the removed singleton keep referencing it, use
+// Rebuild to build them again.
+func (c *Container) Remove(t reflect.Type) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	_, ok := c.providers[t]
+	if !ok && len(c.conditional[t]) == 0 {
+		return &NoProviderError{Type: t}
+	}
+
+	delete(c.providers, t)
+	delete(c.conditional, t)
+
+	return c.EvictInstance(t)
+}
+
+// RemoveNamed unregisters the provider added with AddNamed under name, and disposes
+// its cached singleton, running its cleanup function.
+func (c *Container) RemoveNamed(name string) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	if _, ok := c.named[name]; !ok {
+		return &NoProviderError{Name: name}
+	}
+
+	delete(c.named, name)
+
+	return runCleanups(c.instances().evictNamed(name))
+}
+
+// EvictInstance disposes the cached singleton of t, running its cleanup function,
+// so the next resolution builds it again instead of serving a stale instance, like
+// a client holding rotated credentials. The provider is kept.
+func (c *Container) EvictInstance(t reflect.Type) error {
+	return runCleanups(c.instances().evict(map[reflect.Type]bool{t: true}))
+}
@@ -0,0 +1,194 @@
+package cosmo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRemove(t *testing.T) {
+	c := New()
+	disposed := 0
+	c.AddSingleton(func() (Config, func()) {
+		return Config{URL: DBURL}, func() { disposed++ }
+	})
+	c.Invoke(func(Config) {})
+
+	if err := c.Remove(reflect.TypeFor[Config]()); err != nil {
+		t.Fatal(err.Error())
+	}
+	if disposed != 1 {
+		t.Error("removed singleton was not disposed")
+	}
+	if err := c.Invoke(func(Config) {}); !errors.Is(err, ErrNoProvider) {
+		t.Errorf("removed provider was resolved, err %v", err)
+	}
+
+	c.AddSingleton(func() Config { return Config{URL: "postgres://rotated"} })
+	c.Invoke(func(cfg Config) {
+		if cfg.URL != "postgres://rotated" {
+			t.Error("new provider was not used")
+		}
+	})
+
+	if err := c.Remove(reflect.TypeFor[Validator]()); !errors.Is(err, ErrNoProvider) {
+		t.Error("removing an unknown type did not return ErrNoProvider")
+	}
+}
+
+func TestRemoveNamed(t *testing.T) {
+	c := New()
+	disposed := 0
+	c.AddNamedWithScope(ScopeSingleton, "primary", func() (Config, func()) {
+		return Config{URL: DBURL}, func() { disposed++ }
+	})
+	if _, err := c.resolveNamed(c.Context(), "primary"); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := c.RemoveNamed("primary"); err != nil {
+		t.Fatal(err.Error())
+	}
+	if disposed != 1 {
+		t.Error("removed named singleton was not disposed")
+	}
+	if _, err := c.resolveNamed(c.Context(), "primary"); !errors.Is(err, ErrNoProvider) {
+		t.Error("removed named provider was resolved")
+	}
+	if err := c.RemoveNamed("primary"); !errors.Is(err, ErrNoProvider) {
+		t.Error("removing an unknown name did not return ErrNoProvider")
+	}
+}
+
+func TestEvictInstance(t *testing.T) {
+	c := New()
+	built, disposed := 0, 0
+	c.AddSingleton(func() (Config, func()) {
+		built++
+		return Config{URL: DBURL}, func() { disposed++ }
+	})
+	c.Invoke(func(Config) {})
+
+	if err := c.EvictInstance(reflect.TypeFor[Config]()); err != nil {
+		t.Fatal(err.Error())
+	}
+	if disposed != 1 {
+		t.Error("evicted singleton was not disposed")
+	}
+
+	c.Invoke(func(Config) {})
+	c.Invoke(func(Config) {})
+	if built != 2 {
+		t.Errorf("evicted singleton was built %d times, expected 2", built)
+	}
+}
+
+func TestRegistrationChangesDisposeInstances(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(c *Container, cleanup func())
+		resolve  any
+		change   func(c *Container) error
+	}{
+		{
+			name: "decorate",
+			register: func(c *Container, cleanup func()) {
+				c.AddSingleton(func() (Config, func()) { return Config{URL: DBURL}, cleanup })
+			},
+			resolve: func(Config) {},
+			change: func(c *Container) error {
+				return c.Decorate(func(cfg Config) Config { return cfg })
+			},
+		},
+		{
+			name: "alias",
+			register: func(c *Container, cleanup func()) {
+				c.AddSingleton(func() (DatabaseURL, func()) { return DatabaseURL(DBURL), cleanup })
+				c.Add(func() string { return DBURL })
+			},
+			resolve: func(DatabaseURL) {},
+			change: func(c *Container) error {
+				return c.Alias(reflect.TypeFor[DatabaseURL](), reflect.TypeFor[string]())
+			},
+		},
+		{
+			name: "setters",
+			register: func(c *Container, cleanup func()) {
+				c.AddSingleton(func() (*Notifier, func()) { return &Notifier{}, cleanup })
+				c.Add(func() Config { return Config{URL: DBURL} })
+			},
+			resolve: func(*Notifier) {},
+			change: func(c *Container) error {
+				return c.InjectSetters((*Notifier)(nil), "SetConfig")
+			},
+		},
+		{
+			name: "shadow",
+			register: func(c *Container, cleanup func()) {
+				c.AddSingleton(func() (func(int) int, func()) {
+					return func(n int) int { return n }, cleanup
+				})
+			},
+			resolve: func(func(int) int) {},
+			change: func(c *Container) error {
+				return c.Shadow(func() func(int) int { return func(n int) int { return n } }, func(ShadowReport) {})
+			},
+		},
+		{
+			name: "profiles",
+			register: func(c *Container, cleanup func()) {
+				c.SetProfiles("dev")
+				c.AddWhenWithScope(ScopeSingleton, Profile("dev"), func() (Config, func()) {
+					return Config{URL: DBURL}, cleanup
+				})
+			},
+			resolve: func(Config) {},
+			change: func(c *Container) error {
+				return c.SetProfiles("dev", "test")
+			},
+		},
+		{
+			name: "conditional provider",
+			register: func(c *Container, cleanup func()) {
+				c.SetProfiles("dev")
+				c.AddWhenWithScope(ScopeSingleton, Profile("dev"), func() (Config, func()) {
+					return Config{URL: DBURL}, cleanup
+				})
+			},
+			resolve: func(Config) {},
+			change: func(c *Container) error {
+				return c.AddWhen(Profile("test"), func() Config { return Config{} })
+			},
+		},
+		{
+			name: "scopes",
+			register: func(c *Container, cleanup func()) {
+				c.AddTunable("config", []Scope{ScopeSingleton, ScopeTransient}, func() (Config, func()) {
+					return Config{URL: DBURL}, cleanup
+				})
+			},
+			resolve: func(Config) {},
+			change: func(c *Container) error {
+				return c.SetScopes(map[string]string{"config": "transient"})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			disposed := 0
+			tt.register(c, func() { disposed++ })
+			if err := c.Invoke(tt.resolve); err != nil {
+				t.Fatal(err.Error())
+			}
+
+			if err := tt.change(c); err != nil {
+				t.Fatal(err.Error())
+			}
+			if disposed != 1 {
+				t.Errorf("cached instance was disposed %d times", disposed)
+			}
+		})
+	}
+}
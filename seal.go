@@ -0,0 +1,36 @@
+package cosmo
+
+import "errors"
+
+// ErrSealed is returned when registering a provider in a sealed container.
+var ErrSealed = errors.New("container is sealed")
+
+// Seal makes the registrations of the container read-only: adding providers,
+// configurations, decorators, fallbacks, middlewares or any other setting returns
+// ErrSealed afterwards. Resolutions read the registrations without locks, so they
+// must not change while the container is in use; sealing the container once the
+// application has booted turns such a change into an error instead of a data race.
+// Sealing doesn't change how instances are cached.
+//
+//	if err := app.Register(c); err != nil {
+//		return err
+//	}
+//	c.Seal()
+func (c *Container) Seal() {
+	c.sealed.Store(true)
+}
+
+// Sealed reports whether the container was sealed.
+func (c *Container) Sealed() bool {
+	return c.sealed.Load()
+}
+
+// mutate prepares the registrations to be changed. It returns ErrSealed if the
+// container is sealed, and copies the registrations shared with a clone.
+func (c *Container) mutate() error {
+	if c.sealed.Load() {
+		return ErrSealed
+	}
+	c.own()
+	return nil
+}
@@ -0,0 +1,78 @@
+package cosmo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSeal(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() Config { return Config{URL: DBURL} })
+	c.Seal()
+
+	if !c.Sealed() {
+		t.Error("container was not sealed")
+	}
+
+	for name, err := range map[string]error{
+		"add":       c.Add(func() *Session { return &Session{} }),
+		"named":     c.AddNamed("replica", func() Config { return Config{} }),
+		"group":     c.AddToGroup("validators", func() Validator { return &NotEmptyValidator{} }),
+		"configure": c.Configure("cfg", func() Config { return Config{} }),
+		"decorate":  c.Decorate(func(cfg Config) Config { return cfg }),
+		"redactor":  c.AddRedactor(Config{}, func(v any) any { return v }),
+		"field":     c.RedactField(Config{}, "URL"),
+		"init":      c.InitInBackground(Config{}, 0),
+		"middleware": c.UseResolveMiddleware(func(next ResolveFunc) ResolveFunc {
+			return next
+		}),
+		"hook":       c.OnResolve(func(reflect.Type, time.Duration, error) {}),
+		"profiles":   c.SetProfiles("test"),
+		"adaptation": c.SetPointerAdaptation(true),
+		"metrics":    c.SetMetrics(nil),
+		"tracer":     c.SetTracer(nil),
+		"panics":     c.SetPanicPolicy(PanicFailFast),
+	} {
+		if !errors.Is(err, ErrSealed) {
+			t.Errorf("%s: expected ErrSealed, got %v", name, err)
+		}
+	}
+
+	c.Invoke(func(cfg Config) {
+		if cfg.URL != DBURL {
+			t.Error("sealed container didn't resolve its providers")
+		}
+	})
+}
+
+func TestSealedConcurrentResolution(t *testing.T) {
+	c := New()
+	var built atomic.Int32
+	c.AddSingleton(func() *Session {
+		built.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return &Session{}
+	})
+	c.Seal()
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.resolve(context.Background(), reflect.TypeFor[*Session]()); err != nil {
+				t.Error(err.Error())
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := built.Load(); n != 1 {
+		t.Errorf("singleton was built %d times", n)
+	}
+}
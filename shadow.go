@@ -0,0 +1,178 @@
+package cosmo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// maxShadowCalls is how many calls of a shadow run at a time. Calls made while the
+// limit is reached are not shadowed, so a slow shadow doesn't pile up goroutines.
+const maxShadowCalls = 64
+
+// ShadowReport describes a call where the shadow of a provider returned different
+// results than the primary, or panicked.
+type ShadowReport struct {
+	Type reflect.Type
+	// Args holds the arguments of the call.
+	Args []any
+	// Primary and Shadow hold the results returned by each implementation.
+	Primary []any
+	Shadow  []any
+	// Panic holds the value the shadow panicked with, if it did.
+	Panic any
+}
+
+// Shadow runs the implementation built by constructor alongside the primary provider
+// of its type, to verify a rewrite with production traffic. Calls go to the primary,
+// and the shadow is called asynchronously with the same arguments; when the results
+// differ, according to reflect.DeepEqual, or the shadow panics, report is called.
+//
+//	type Pricer func(ctx context.Context, sku string) (Price, error)
+//
+//	c.AddSingleton(NewPricer)
+//	c.Shadow(NewPricerV2, func(r cosmo.ShadowReport) {
+//		log.Printf("pricer mismatch: %v returned %v, expected %v", r.Args, r.Shadow, r.Primary)
+//	})
+//
+// The constructor must return a function type, or the function type and an error,
+// and its arguments are resolved from the container. Since Go can't implement
+// interfaces at runtime, interfaces must be exposed as function types to be shadowed.
+// Context arguments passed to the shadow are not canceled when the primary returns,
+// and other arguments are shared, so they must not be modified by the
+// implementations.
+//
+// At most 64 shadow calls run at a time, calls made while they run are only served
+// by the primary. Once the container is closed, Close waits for the running shadow
+// calls and no more calls are shadowed.
+func (c *Container) Shadow(constructor any, report func(ShadowReport)) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	v := reflect.ValueOf(constructor)
+	if v.Kind() != reflect.Func {
+		return errors.New("shadow constructor must be a function")
+	}
+
+	ct := v.Type()
+	if ct.IsVariadic() || ct.NumOut() == 0 || ct.NumOut() > 2 || (ct.NumOut() == 2 && ct.Out(1) != errorType) {
+		return errors.New("shadow constructor must return T or (T, error)")
+	}
+	t := ct.Out(0)
+	if t.Kind() != reflect.Func {
+		return fmt.Errorf("shadowed type %v must be a function type", t)
+	}
+
+	runner := &shadowRunner{sem: make(chan struct{}, maxShadowCalls)}
+	c.onClose(runner.stop)
+
+	// The shadow is applied as a decorator receiving the primary and the arguments of
+	// the constructor, so they are resolved like the ones of any decorator.
+	in := []reflect.Type{t}
+	for i := 0; i < ct.NumIn(); i++ {
+		in = append(in, ct.In(i))
+	}
+	decoratorType := reflect.FuncOf(in, []reflect.Type{t, errorType}, false)
+	decorator := reflect.MakeFunc(decoratorType, func(args []reflect.Value) []reflect.Value {
+		out := v.Call(args[1:])
+		if len(out) == 2 && !out[1].IsNil() {
+			return []reflect.Value{reflect.Zero(t), out[1]}
+		}
+		return []reflect.Value{runner.shadowed(t, args[0], out[0], report), reflect.Zero(errorType)}
+	})
+
+	c.decorators[t] = append(c.decorators[t], decorator)
+	return c.EvictInstance(t)
+}
+
+// shadowRunner bounds the shadow calls running in the background, and stops them
+// when the container is closed.
+type shadowRunner struct {
+	mu      sync.Mutex
+	stopped bool
+	running sync.WaitGroup
+	sem     chan struct{}
+}
+
+// start reserves a slot for a shadow call, returning false if the runner is stopped
+// or every slot is taken. The caller must call done once the shadow call returns.
+func (sr *shadowRunner) start() bool {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	if sr.stopped {
+		return false
+	}
+	select {
+	case sr.sem <- struct{}{}:
+		sr.running.Add(1)
+		return true
+	default:
+		return false
+	}
+}
+
+// done releases the slot of a shadow call.
+func (sr *shadowRunner) done() {
+	<-sr.sem
+	sr.running.Done()
+}
+
+// stop waits for the running shadow calls and prevents new ones.
+func (sr *shadowRunner) stop() {
+	sr.mu.Lock()
+	sr.stopped = true
+	sr.mu.Unlock()
+	sr.running.Wait()
+}
+
+// shadowed returns a function of type t calling primary, that calls shadow in the
+// background and reports the calls where their results differ.
+func (sr *shadowRunner) shadowed(t reflect.Type, primary, shadow reflect.Value, report func(ShadowReport)) reflect.Value {
+	return reflect.MakeFunc(t, func(args []reflect.Value) []reflect.Value {
+		out := callFunc(primary, args)
+		if !sr.start() {
+			return out
+		}
+
+		shadowArgs := make([]reflect.Value, len(args))
+		for i, arg := range args {
+			if arg.Type() == contextType && !arg.IsNil() {
+				arg = reflect.ValueOf(context.WithoutCancel(arg.Interface().(context.Context)))
+			}
+			shadowArgs[i] = arg
+		}
+
+		go func() {
+			defer sr.done()
+			r := ShadowReport{
+				Type:    t,
+				Args:    interfaces(args),
+				Primary: interfaces(out),
+			}
+			defer func() {
+				if p := recover(); p != nil {
+					r.Panic = p
+					report(r)
+				}
+			}()
+
+			r.Shadow = interfaces(callFunc(shadow, shadowArgs))
+			if !reflect.DeepEqual(r.Primary, r.Shadow) {
+				report(r)
+			}
+		}()
+
+		return out
+	})
+}
+
+// interfaces returns the values held by vs.
+func interfaces(vs []reflect.Value) []any {
+	out := make([]any, len(vs))
+	for i, v := range vs {
+		out[i] = v.Interface()
+	}
+	return out
+}
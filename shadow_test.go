@@ -0,0 +1,126 @@
+package cosmo
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+type Slugify func(ctx context.Context, title string) string
+
+func TestShadow(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() Slugify {
+		return func(ctx context.Context, title string) string {
+			return strings.ToLower(strings.ReplaceAll(title, " ", "-"))
+		}
+	})
+
+	reports := make(chan ShadowReport, 1)
+	err := c.Shadow(func(cfg Config) Slugify {
+		return func(ctx context.Context, title string) string {
+			if ctx.Err() != nil {
+				return "canceled"
+			}
+			return strings.ToLower(strings.Join(strings.Fields(title), "-"))
+		}
+	}, func(r ShadowReport) { reports <- r })
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	c.AddSingleton(func() Config { return Config{URL: DBURL} })
+
+	var slugify Slugify
+	if err := c.Invoke(func(s Slugify) { slugify = s }); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if got := slugify(ctx, "Hello World"); got != "hello-world" {
+		t.Errorf("wrong primary result %q", got)
+	}
+	cancel()
+	if got := slugify(context.Background(), "Hello  World"); got != "hello--world" {
+		t.Errorf("shadow result was returned %q", got)
+	}
+
+	r := <-reports
+	if r.Type != reflect.TypeFor[Slugify]() || r.Args[1] != "Hello  World" {
+		t.Errorf("wrong report %+v", r)
+	}
+	if r.Primary[0] != "hello--world" || r.Shadow[0] != "hello-world" {
+		t.Errorf("wrong report results primary=%v shadow=%v", r.Primary, r.Shadow)
+	}
+	select {
+	case r := <-reports:
+		t.Errorf("matching call was reported %+v", r)
+	default:
+	}
+}
+
+func TestShadowPanic(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() Slugify {
+		return func(ctx context.Context, title string) string { return title }
+	})
+	reports := make(chan ShadowReport, 1)
+	c.Shadow(func() Slugify {
+		return func(ctx context.Context, title string) string { panic("not implemented") }
+	}, func(r ShadowReport) { reports <- r })
+
+	c.Invoke(func(s Slugify) {
+		if got := s(context.Background(), "title"); got != "title" {
+			t.Errorf("wrong primary result %q", got)
+		}
+	})
+
+	if r := <-reports; r.Panic != "not implemented" {
+		t.Errorf("shadow panic was not reported %+v", r)
+	}
+}
+
+func TestShadowInvalid(t *testing.T) {
+	c := New()
+	if err := c.Shadow(func() DBService { return nil }, func(ShadowReport) {}); err == nil {
+		t.Error("shadow of an interface did not return error")
+	}
+	if err := c.Shadow("not a function", func(ShadowReport) {}); err == nil {
+		t.Error("shadow of a non function did not return error")
+	}
+}
+
+func TestShadowBounded(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() Slugify {
+		return func(ctx context.Context, title string) string { return title }
+	})
+	release := make(chan struct{})
+	var started, finished atomic.Int32
+	c.Shadow(func() Slugify {
+		return func(ctx context.Context, title string) string {
+			started.Add(1)
+			<-release
+			finished.Add(1)
+			return title
+		}
+	}, func(ShadowReport) {})
+
+	var slugify Slugify
+	c.Invoke(func(s Slugify) { slugify = s })
+	for range 2 * maxShadowCalls {
+		slugify(context.Background(), "title")
+	}
+
+	close(release)
+	c.Close()
+	if n := finished.Load(); n != maxShadowCalls {
+		t.Errorf("%d shadow calls ran, expected %d", n, maxShadowCalls)
+	}
+
+	slugify(context.Background(), "title")
+	if n := started.Load(); n != maxShadowCalls {
+		t.Error("call was shadowed after the container was closed")
+	}
+}
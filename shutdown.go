@@ -0,0 +1,147 @@
+package cosmo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ShutdownStep describes a function run when the container was shut down: a
+// cleanup of an instance, or a facility like a warm pool being stopped.
+type ShutdownStep struct {
+	// Order is the position of the step, starting at 1.
+	Order int
+	// Type is the type of the disposed instance, it's nil for facilities.
+	Type reflect.Type
+	// Name and Group are set for named providers and group members.
+	Name  string
+	Group string
+	// Constructor is the name of the constructor of the instance.
+	Constructor string
+	Duration    time.Duration
+	Err         error
+	// TimedOut reports whether the step was still running when the context of
+	// Shutdown was done. The following steps are started without waiting for it.
+	TimedOut bool
+}
+
+// ShutdownReport lists the steps run by Shutdown, in order.
+type ShutdownReport struct {
+	Steps    []ShutdownStep
+	Duration time.Duration
+}
+
+// Err returns the errors of the steps, including the steps that timed out.
+func (r ShutdownReport) Err() error {
+	var errs []error
+	for _, step := range r.Steps {
+		if step.Err != nil {
+			errs = append(errs, step.Err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// String formats the report, one step per line.
+func (r ShutdownReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "shutdown took %v\n", r.Duration)
+	for _, step := range r.Steps {
+		what := "facility"
+		if step.Type != nil {
+			what = step.Type.String()
+		}
+		switch {
+		case step.Name != "":
+			what += fmt.Sprintf(" (name %s)", step.Name)
+		case step.Group != "":
+			what += fmt.Sprintf(" (group %s)", step.Group)
+		}
+
+		status := "ok"
+		switch {
+		case step.TimedOut:
+			status = "timed out"
+		case step.Err != nil:
+			status = "error: " + step.Err.Error()
+		}
+		fmt.Fprintf(&sb, "%d. %s %s: %v, %s\n", step.Order, what, step.Constructor, step.Duration, status)
+	}
+	return sb.String()
+}
+
+// Shutdown closes the container like Close, timing each step, and returns a report
+// of the steps. When ctx is done, the running step is reported as timed out and the
+// remaining steps are started without waiting for them to finish.
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	report := c.Shutdown(ctx)
+//	log.Print(report)
+func (c *Container) Shutdown(ctx context.Context) ShutdownReport {
+	start := time.Now()
+
+	c.mu.Lock()
+	closers := c.closers
+	c.closers = nil
+	c.mu.Unlock()
+
+	var report ShutdownReport
+	run := func(step ShutdownStep, fn func() error) {
+		step.Order = len(report.Steps) + 1
+		runStep(ctx, &step, fn)
+		report.Steps = append(report.Steps, step)
+	}
+
+	for i := len(closers) - 1; i >= 0; i-- {
+		closer := closers[i]
+		run(ShutdownStep{}, func() error {
+			closer()
+			return nil
+		})
+	}
+
+	cleanups := c.cache.Swap(newCache()).takeCleanups()
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		spec := cleanups[i].spec
+		run(ShutdownStep{
+			Type:        spec.Type,
+			Name:        spec.Name,
+			Group:       spec.Group,
+			Constructor: funcName(spec.Value),
+		}, cleanups[i].fn)
+	}
+
+	report.Duration = time.Since(start)
+	return report
+}
+
+// runStep runs fn, waiting for it at most until ctx is done, and records its
+// duration and result in step.
+func runStep(ctx context.Context, step *ShutdownStep, fn func() error) {
+	start := time.Now()
+	defer func() { step.Duration = time.Since(start) }()
+
+	if ctx.Done() == nil {
+		step.Err = fn()
+		return
+	}
+
+	if err := ctx.Err(); err != nil {
+		go fn()
+		step.Err, step.TimedOut = err, true
+		return
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- fn() }()
+
+	select {
+	case step.Err = <-done:
+	case <-ctx.Done():
+		step.Err, step.TimedOut = ctx.Err(), true
+	}
+}
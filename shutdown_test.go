@@ -0,0 +1,64 @@
+package cosmo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShutdownReport(t *testing.T) {
+	c := New()
+	release := make(chan struct{})
+	defer close(release)
+
+	c.AddSingleton(func() (Config, func()) {
+		return Config{}, func() {}
+	})
+	c.AddSingleton(func(cfg Config) (*Session, func()) {
+		return &Session{}, func() { <-release }
+	})
+	c.AddToGroupWithScope(ScopeSingleton, "routes", func() *RouteHandler {
+		return &RouteHandler{Name: "users", closed: &[]string{}}
+	})
+	c.AddToGroupWithScope(ScopeSingleton, "routes", func() (*RouteHandler, error) {
+		return &RouteHandler{Name: "orders", closed: &[]string{}}, nil
+	})
+
+	if err := c.Invoke(func(*Session, []*RouteHandler) {}); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	report := c.Shutdown(ctx)
+
+	var types []reflect.Type
+	for i, step := range report.Steps {
+		if step.Order != i+1 {
+			t.Errorf("step %d has order %d", i, step.Order)
+		}
+		types = append(types, step.Type)
+	}
+	expected := []reflect.Type{
+		reflect.TypeFor[*RouteHandler](),
+		reflect.TypeFor[*RouteHandler](),
+		reflect.TypeFor[*Session](),
+		reflect.TypeFor[Config](),
+	}
+	if !reflect.DeepEqual(types, expected) {
+		t.Fatalf("unexpected steps: %v", types)
+	}
+
+	if !report.Steps[2].TimedOut || !errors.Is(report.Err(), context.DeadlineExceeded) {
+		t.Error("blocked cleanup was not reported as timed out")
+	}
+	if report.Steps[3].Err == nil || !report.Steps[3].TimedOut {
+		t.Error("cleanup after the deadline was not reported as timed out")
+	}
+	if !strings.Contains(report.String(), "timed out") {
+		t.Errorf("report doesn't mention the timeout:\n%s", report)
+	}
+}
@@ -0,0 +1,79 @@
+package cosmo
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// TagName is the struct tag used by Bind to configure how a field is resolved.
+//
+//	type Deps struct {
+//		DB         DBService   `cosmo:"name=replica"`
+//		Validators []Validator `cosmo:"group=validators"`
+//		Tracer     Tracer      `cosmo:"optional"`
+//		Internal   Helper      `cosmo:"-"`
+//		Handlers   Handlers    `cosmo:"inline"`
+//	}
+//
+// The `cosmo:"secret"` option marks fields that must be redacted when the container
+// prints values, see Container.Sanitize.
+const TagName = "cosmo"
+
+// fieldTag holds the options parsed from a struct field tag.
+type fieldTag struct {
+	skip     bool
+	name     string
+	group    string
+	optional bool
+	secret   bool
+	inline   bool
+}
+
+// parseTag parses the cosmo tag of a struct field.
+func parseTag(field reflect.StructField) (fieldTag, error) {
+	var tag fieldTag
+
+	value, ok := field.Tag.Lookup(TagName)
+	if !ok {
+		return tag, nil
+	}
+
+	if value == "-" {
+		tag.skip = true
+		return tag, nil
+	}
+
+	for _, option := range strings.Split(value, ",") {
+		key, arg, _ := strings.Cut(strings.TrimSpace(option), "=")
+		switch key {
+		case "":
+		case "name":
+			tag.name = arg
+		case "group":
+			tag.group = arg
+		case "optional":
+			tag.optional = true
+		case "secret":
+			tag.secret = true
+		case "inline":
+			tag.inline = true
+		default:
+			return tag, fmt.Errorf("unknown option %q in tag of field %s", key, field.Name)
+		}
+	}
+
+	if tag.name != "" && tag.group != "" {
+		return tag, fmt.Errorf("field %s can't have both name and group options", field.Name)
+	}
+
+	if tag.inline && (tag.name != "" || tag.group != "" || tag.optional) {
+		return tag, fmt.Errorf("inline field %s can't have name, group or optional options", field.Name)
+	}
+	if tag.inline && field.Type.Kind() != reflect.Struct &&
+		(field.Type.Kind() != reflect.Pointer || field.Type.Elem().Kind() != reflect.Struct) {
+		return tag, fmt.Errorf("inline field %s must be a struct or a pointer to a struct", field.Name)
+	}
+
+	return tag, nil
+}
@@ -0,0 +1,147 @@
+package cosmo
+
+import "testing"
+
+type Cache interface {
+	Get(key string) (string, bool)
+}
+
+type ToBindTags struct {
+	Primary    DBService   `cosmo:"name=primary"`
+	Replica    DBService   `cosmo:"name=replica"`
+	Config     Config      `cosmo:"name=DBConfig"`
+	Validators []Validator `cosmo:"group=validators"`
+	Cache      Cache       `cosmo:"optional"`
+	Skipped    DBService   `cosmo:"-"`
+	db         DBService
+}
+
+func TestBindTags(t *testing.T) {
+	c := New()
+	c.Configure("DBConfig", func() Config {
+		return Config{
+			URL: DBURL,
+		}
+	})
+	c.AddNamedWithScope(ScopeSingleton, "primary", func(cfg Config) DBService {
+		return &SQLDBService{
+			Config: cfg,
+		}
+	})
+	c.AddNamed("replica", func() DBService {
+		return &SQLDBService{
+			Config: Config{URL: "sqlite://replica.db"},
+		}
+	})
+	c.AddToGroup("validators", func() Validator {
+		return &NotEmptyValidator{}
+	})
+
+	var bnd ToBindTags
+	if err := c.Bind(&bnd); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if bnd.Primary.(*SQLDBService).Config.URL != DBURL {
+		t.Error("wrong value bound to primary")
+	}
+	if bnd.Replica.(*SQLDBService).Config.URL != "sqlite://replica.db" {
+		t.Error("wrong value bound to replica")
+	}
+	if bnd.Config.URL != DBURL {
+		t.Error("wrong value bound to configuration key")
+	}
+	if len(bnd.Validators) != 1 {
+		t.Error("group was not bound")
+	}
+	if bnd.Cache != nil || bnd.Skipped != nil || bnd.db != nil {
+		t.Error("optional, skipped or unexported fields were set")
+	}
+}
+
+func TestBindTagErrors(t *testing.T) {
+	c := New()
+
+	var missing struct {
+		DB DBService `cosmo:"name=primary"`
+	}
+	if err := c.Bind(&missing); err == nil {
+		t.Error("missing named provider did not return error")
+	}
+
+	var unknown struct {
+		DB DBService `cosmo:"nmae=primary"`
+	}
+	if err := c.Bind(&unknown); err == nil {
+		t.Error("unknown tag option did not return error")
+	}
+
+	if err := c.Bind(ToBindTags{}); err == nil {
+		t.Error("bind accepted a value that is not a pointer to a struct")
+	}
+}
+
+type UserHandlers struct {
+	DB DBService
+}
+
+type OrderHandlers struct {
+	Config Config
+}
+
+type handlerDeps struct {
+	Config Config
+}
+
+type ToBindInline struct {
+	handlerDeps
+	Users  UserHandlers   `cosmo:"inline"`
+	Orders *OrderHandlers `cosmo:"inline"`
+}
+
+func TestBindInline(t *testing.T) {
+	c := New()
+	c.AddSingleton(func() Config { return Config{URL: DBURL} })
+	c.Add(func(cfg Config) DBService { return &SQLDBService{Config: cfg} })
+
+	var handlers ToBindInline
+	if err := c.Bind(&handlers); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if handlers.Users.DB == nil {
+		t.Error("inline struct field was not bound")
+	}
+	if handlers.Orders == nil || handlers.Orders.Config.URL != DBURL {
+		t.Error("inline struct pointer was not allocated and bound")
+	}
+	if handlers.Config.URL != DBURL {
+		t.Error("embedded struct field was not bound")
+	}
+}
+
+type recursiveInline struct {
+	Next *recursiveInline `cosmo:"inline"`
+}
+
+func TestBindInlineErrors(t *testing.T) {
+	c := New()
+
+	var notStruct struct {
+		DB DBService `cosmo:"inline"`
+	}
+	if err := c.Bind(&notStruct); err == nil {
+		t.Error("inline field that is not a struct did not return error")
+	}
+
+	var named struct {
+		Users UserHandlers `cosmo:"inline,name=users"`
+	}
+	if err := c.Bind(&named); err == nil {
+		t.Error("inline field with a name did not return error")
+	}
+
+	if err := c.Bind(&recursiveInline{}); err == nil {
+		t.Error("recursive inline struct did not return error")
+	}
+}
@@ -0,0 +1,63 @@
+package cosmo
+
+import (
+	"context"
+	"reflect"
+)
+
+// Tracer starts a span around every constructor call and every invocation. The span
+// name is the type being built, or the invoked function type, and chain holds the
+// types being resolved when the span started, the outermost first. The returned
+// context is used to resolve the dependencies, so their spans are children of
+// this one, and end is called with the error of the call, if any.
+//
+// An OpenTelemetry tracer can be adapted with:
+//
+//	type otelTracer struct{ tracer trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string, chain []reflect.Type) (context.Context, func(error)) {
+//		types := make([]string, len(chain))
+//		for i, t := range chain {
+//			types[i] = t.String()
+//		}
+//		ctx, span := t.tracer.Start(ctx, name, trace.WithAttributes(attribute.StringSlice("cosmo.chain", types)))
+//		return ctx, func(err error) {
+//			if err != nil {
+//				span.RecordError(err)
+//				span.SetStatus(codes.Error, err.Error())
+//			}
+//			span.End()
+//		}
+//	}
+type Tracer interface {
+	Start(ctx context.Context, name string, chain []reflect.Type) (context.Context, func(err error))
+}
+
+// SetTracer sets the Tracer used by the container.
+func (c *Container) SetTracer(tracer Tracer) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	if tracer == nil {
+		tracer = nopTracer{}
+	}
+	c.tracer = tracer
+	return nil
+}
+
+// startSpan starts a span of the tracer named by name. Building the name and the
+// chain of the span is skipped when no tracer is set.
+func (c *Container) startSpan(ctx context.Context, name func() string) (context.Context, func(error)) {
+	if _, nop := c.tracer.(nopTracer); nop {
+		return ctx, endNop
+	}
+	return c.tracer.Start(ctx, name(), Chain(ctx))
+}
+
+func endNop(error) {}
+
+type nopTracer struct{}
+
+func (nopTracer) Start(ctx context.Context, name string, chain []reflect.Type) (context.Context, func(error)) {
+	return ctx, func(error) {}
+}
@@ -0,0 +1,106 @@
+package cosmo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type recordedSpan struct {
+	name  string
+	chain []reflect.Type
+	err   error
+	ended bool
+}
+
+type recordingTracer struct {
+	spans []*recordedSpan
+}
+
+func (tr *recordingTracer) Start(ctx context.Context, name string, chain []reflect.Type) (context.Context, func(error)) {
+	span := &recordedSpan{name: name, chain: chain}
+	tr.spans = append(tr.spans, span)
+	return ctx, func(err error) {
+		span.err = err
+		span.ended = true
+	}
+}
+
+func TestTracer(t *testing.T) {
+	c := New()
+	tracer := &recordingTracer{}
+	c.SetTracer(tracer)
+
+	c.AddSingleton(func() Config {
+		return Config{
+			URL: DBURL,
+		}
+	})
+	c.Add(func(cfg Config) DBService {
+		return &SQLDBService{
+			Config: cfg,
+		}
+	})
+
+	if err := c.Invoke(func(db DBService) {}); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(tracer.spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(tracer.spans))
+	}
+
+	if !strings.HasPrefix(tracer.spans[0].name, "invoke ") {
+		t.Errorf("wrong invoke span name %s", tracer.spans[0].name)
+	}
+
+	cfgSpan := tracer.spans[2]
+	expected := []reflect.Type{reflect.TypeFor[DBService](), reflect.TypeFor[Config]()}
+	if cfgSpan.name != "cosmo.Config" || !reflect.DeepEqual(cfgSpan.chain, expected) {
+		t.Errorf("wrong constructor span %s %v", cfgSpan.name, cfgSpan.chain)
+	}
+
+	for _, span := range tracer.spans {
+		if !span.ended {
+			t.Errorf("span %s was not ended", span.name)
+		}
+	}
+}
+
+func TestTracerError(t *testing.T) {
+	c := New()
+	tracer := &recordingTracer{}
+	c.SetTracer(tracer)
+
+	expected := errors.New("dial failed")
+	c.Add(func() (DBService, error) {
+		return nil, expected
+	})
+
+	c.Invoke(func(db DBService) {})
+
+	for _, span := range tracer.spans {
+		if !errors.Is(span.err, expected) {
+			t.Errorf("span %s did not receive the error", span.name)
+		}
+	}
+}
+
+func TestChain(t *testing.T) {
+	c := New()
+	c.Add(func(ctx context.Context) Config {
+		chain := Chain(ctx)
+		if len(chain) != 2 || chain[0] != reflect.TypeFor[DBService]() {
+			t.Errorf("wrong chain %v", chain)
+		}
+		return Config{}
+	})
+	c.Add(func(cfg Config) DBService {
+		return &SQLDBService{
+			Config: cfg,
+		}
+	})
+	c.Invoke(func(db DBService) {})
+}
@@ -0,0 +1,97 @@
+package cosmo
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// tunable holds the providers registered by a constructor added with AddTunable,
+// and the scopes it allows.
+type tunable struct {
+	specs   []Spec
+	allowed []Scope
+}
+
+// AddTunable adds the constructor with a scope chosen by configuration, under key,
+// among the allowed scopes. The first allowed scope is used until SetScopes sets
+// another one, so performance and isolation trade-offs can be tuned per environment:
+//
+//	c.AddTunable("cache", []cosmo.Scope{cosmo.ScopeSingleton, cosmo.ScopeTransient}, NewCache)
+//	err := c.SetScopes(map[string]string{"cache": os.Getenv("CACHE_SCOPE")})
+func (c *Container) AddTunable(key string, allowed []Scope, constructor any) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+	if len(allowed) == 0 {
+		return fmt.Errorf("tunable %q allows no scope", key)
+	}
+	for _, scope := range allowed {
+		if err := checkScope(scope); err != nil {
+			return err
+		}
+	}
+	if _, ok := c.tunable[key]; ok {
+		return fmt.Errorf("tunable %q is already registered", key)
+	}
+
+	_, v, err := spec(constructor)
+	if err != nil {
+		return err
+	}
+
+	t := tunable{allowed: allowed}
+	for _, provider := range c.specs(allowed[0], v) {
+		c.providers[provider.Type] = provider
+		t.specs = append(t.specs, provider)
+	}
+	c.tunable[key] = t
+
+	return nil
+}
+
+// SetScopes sets the scopes of the providers added with AddTunable, from a map of
+// their keys to scope names, as accepted by ParseScope. Empty names are ignored. It
+// returns an error for unknown keys and for scopes the provider doesn't allow, without
+// changing any scope. SetScopes is meant to be called when the container is built,
+// before resolving the providers.
+func (c *Container) SetScopes(scopes map[string]string) error {
+	if err := c.mutate(); err != nil {
+		return err
+	}
+
+	parsed := make(map[string]Scope, len(scopes))
+	for key, name := range scopes {
+		if name == "" {
+			continue
+		}
+		t, ok := c.tunable[key]
+		if !ok {
+			return fmt.Errorf("no tunable provider %q", key)
+		}
+		scope, err := ParseScope(name)
+		if err != nil {
+			return fmt.Errorf("tunable %q: %w", key, err)
+		}
+		if !slices.Contains(t.allowed, scope) {
+			return fmt.Errorf("tunable %q doesn't allow scope %v, allowed scopes are %v", key, scope, t.allowed)
+		}
+		parsed[key] = scope
+	}
+
+	types := make(map[reflect.Type]bool)
+	for key, scope := range parsed {
+		for _, spec := range c.tunable[key].specs {
+			provider, ok := c.providers[spec.Type]
+			if !ok || provider.order != spec.order {
+				// The provider was removed or replaced by another constructor.
+				continue
+			}
+			provider.Scope = scope
+			c.providers[spec.Type] = provider
+			types[spec.Type] = true
+		}
+	}
+
+	return runCleanups(c.instances().evict(types))
+}
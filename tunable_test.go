@@ -0,0 +1,66 @@
+package cosmo
+
+import "testing"
+
+type tunableCache struct{}
+type tunableSession struct{}
+
+func TestTunableScope(t *testing.T) {
+	c := New()
+	built := 0
+	err := c.AddTunable("cache", []Scope{ScopeSingleton, ScopeTransient}, func() *tunableCache {
+		built++
+		return &tunableCache{}
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	c.Invoke(func(*tunableCache) {})
+	c.Invoke(func(*tunableCache) {})
+	if built != 1 {
+		t.Error("tunable provider didn't use its default scope")
+	}
+
+	if err := c.SetScopes(map[string]string{"cache": "transient"}); err != nil {
+		t.Fatal(err.Error())
+	}
+	c.Invoke(func(*tunableCache) {})
+	c.Invoke(func(*tunableCache) {})
+	if built != 3 {
+		t.Error("tunable provider didn't use the configured scope")
+	}
+
+	if err := c.SetScopes(map[string]string{"unknown": "singleton"}); err == nil {
+		t.Error("unknown tunable key was accepted")
+	}
+
+	c.AddTunable("session", []Scope{ScopeTransient}, func() *tunableSession { return &tunableSession{} })
+	if err := c.SetScopes(map[string]string{"session": "singleton", "cache": "singleton"}); err == nil {
+		t.Error("scope outside the allow-list was accepted")
+	}
+	if provider, _ := c.Provider(TypeOf((*tunableCache)(nil))); provider.Scope != ScopeTransient {
+		t.Error("invalid scopes changed the scope of other providers")
+	}
+}
+
+func TestTunableReplaced(t *testing.T) {
+	c := New()
+	c.AddTunable("cache", []Scope{ScopeSingleton, ScopeTransient}, func() *tunableCache {
+		return &tunableCache{}
+	})
+	built := 0
+	c.AddSingleton(func() *tunableCache {
+		built++
+		return &tunableCache{}
+	})
+
+	if err := c.SetScopes(map[string]string{"cache": "transient"}); err != nil {
+		t.Fatal(err.Error())
+	}
+	c.Invoke(func(*tunableCache) {})
+	c.Invoke(func(*tunableCache) {})
+	if built != 1 {
+		t.Error("scope of a provider replaced after AddTunable was changed")
+	}
+}
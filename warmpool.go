@@ -0,0 +1,210 @@
+package cosmo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ErrPoolClosed is returned by WarmPool.Checkout after the pool is closed.
+var ErrPoolClosed = errors.New("warm pool is closed")
+
+// WarmPoolRetryDelay is how long a WarmPool waits to build an instance again after
+// its constructor fails.
+var WarmPoolRetryDelay = time.Second
+
+// WarmPool keeps a fixed number of instances of a transient type, built in the
+// background, that are checked out and checked back in by their users. It's meant
+// for heavy instances, like embedded interpreters or ML sessions, that are too
+// expensive to build on demand but can't be shared concurrently.
+//
+//	pool, err := cosmo.NewWarmPool[*Interpreter](c, 4, func(i *Interpreter) bool {
+//		return i.Alive()
+//	})
+//
+//	interp, err := pool.Checkout(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	defer pool.Checkin(interp)
+//
+// Instances that are not healthy on checkout or checkin are discarded, closing them
+// if they implement io.Closer, and replaced in the background. The pool is closed
+// when the container is closed.
+type WarmPool[T any] struct {
+	c        *Container
+	healthy  func(T) bool
+	idle     chan T
+	requests chan struct{}
+	done     chan struct{}
+	wg       sync.WaitGroup
+	once     sync.Once
+	mu       sync.Mutex
+	err      error
+}
+
+// NewWarmPool creates a pool with size instances of T, which must be registered with
+// ScopeTransient. healthy can be nil, in which case instances are always reused.
+func NewWarmPool[T any](c *Container, size int, healthy func(T) bool) (*WarmPool[T], error) {
+	if size <= 0 {
+		return nil, errors.New("warm pool size must be positive")
+	}
+
+	t := reflect.TypeFor[T]()
+	provider, ok := c.Provider(t)
+	if !ok {
+		return nil, &NoProviderError{Type: t}
+	}
+	if provider.Scope != ScopeTransient {
+		return nil, fmt.Errorf("warm pool of %v requires ScopeTransient, got %v", t, provider.Scope)
+	}
+
+	if healthy == nil {
+		healthy = func(T) bool { return true }
+	}
+
+	p := &WarmPool[T]{
+		c:        c,
+		healthy:  healthy,
+		idle:     make(chan T, size),
+		requests: make(chan struct{}, size),
+		done:     make(chan struct{}),
+	}
+
+	for i := 0; i < size; i++ {
+		p.requests <- struct{}{}
+	}
+
+	p.wg.Add(1)
+	go p.fill()
+	c.onClose(p.Close)
+
+	return p, nil
+}
+
+// fill builds an instance for every request until the pool is closed.
+func (p *WarmPool[T]) fill() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-p.requests:
+		}
+
+		var v T
+		err := p.c.Invoke(func(instance T) { v = instance })
+
+		p.mu.Lock()
+		p.err = err
+		p.mu.Unlock()
+
+		if err != nil {
+			select {
+			case <-p.done:
+				return
+			case <-time.After(WarmPoolRetryDelay):
+			}
+			p.requests <- struct{}{}
+			continue
+		}
+
+		select {
+		case p.idle <- v:
+		case <-p.done:
+			discard(v)
+			return
+		}
+	}
+}
+
+// Checkout returns an idle instance, waiting until one is available or ctx is done.
+// The instance must be returned to the pool with Checkin.
+func (p *WarmPool[T]) Checkout(ctx context.Context) (T, error) {
+	var zero T
+
+	for {
+		select {
+		case <-p.done:
+			return zero, ErrPoolClosed
+		case <-ctx.Done():
+			if err := p.Err(); err != nil {
+				return zero, fmt.Errorf("%w: %w", ctx.Err(), err)
+			}
+			return zero, ctx.Err()
+		case v := <-p.idle:
+			if p.healthy(v) {
+				return v, nil
+			}
+			p.replace(v)
+		}
+	}
+}
+
+// Checkin returns an instance obtained with Checkout to the pool. Unhealthy
+// instances are replaced.
+func (p *WarmPool[T]) Checkin(v T) {
+	select {
+	case <-p.done:
+		discard(v)
+		return
+	default:
+	}
+
+	if !p.healthy(v) {
+		p.replace(v)
+		return
+	}
+
+	select {
+	case p.idle <- v:
+	default:
+		discard(v)
+	}
+}
+
+// Err returns the error of the last attempt to build an instance, or nil if it
+// succeeded.
+func (p *WarmPool[T]) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// Close stops building instances and discards the idle ones. Instances checked in
+// after the pool is closed are discarded.
+func (p *WarmPool[T]) Close() {
+	p.once.Do(func() {
+		close(p.done)
+		p.wg.Wait()
+		for {
+			select {
+			case v := <-p.idle:
+				discard(v)
+			default:
+				return
+			}
+		}
+	})
+}
+
+// replace discards v and requests a new instance.
+func (p *WarmPool[T]) replace(v T) {
+	discard(v)
+	select {
+	case p.requests <- struct{}{}:
+	default:
+	}
+}
+
+// discard closes v if it's an io.Closer.
+func discard(v any) {
+	if closer, ok := v.(io.Closer); ok {
+		closer.Close()
+	}
+}
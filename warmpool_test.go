@@ -0,0 +1,94 @@
+package cosmo
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type Session struct {
+	ID     int64
+	Broken bool
+	Closed bool
+}
+
+func (s *Session) Close() error {
+	s.Closed = true
+	return nil
+}
+
+func TestWarmPool(t *testing.T) {
+	c := New()
+	var built atomic.Int64
+	c.Add(func() *Session {
+		return &Session{ID: built.Add(1)}
+	})
+
+	pool, err := NewWarmPool[*Session](c, 2, func(s *Session) bool {
+		return !s.Broken
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	first, err := pool.Checkout(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	second, err := pool.Checkout(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	short, cancelShort := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelShort()
+	if _, err := pool.Checkout(short); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("checkout of an exhausted pool returned %v", err)
+	}
+
+	second.Broken = true
+	pool.Checkin(second)
+	if !second.Closed {
+		t.Error("unhealthy instance was not closed")
+	}
+
+	replacement, err := pool.Checkout(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if replacement.ID != 3 {
+		t.Errorf("unhealthy instance was not replaced, got %d", replacement.ID)
+	}
+
+	pool.Checkin(first)
+	c.Close()
+
+	if !first.Closed {
+		t.Error("idle instance was not closed with the container")
+	}
+	if _, err := pool.Checkout(ctx); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("checkout after close returned %v", err)
+	}
+
+	pool.Checkin(replacement)
+	if !replacement.Closed {
+		t.Error("instance checked in after close was not closed")
+	}
+}
+
+func TestWarmPoolInvalid(t *testing.T) {
+	c := New()
+	if _, err := NewWarmPool[*Session](c, 1, nil); err == nil {
+		t.Error("pool of a type without provider was created")
+	}
+
+	c.AddSingleton(func() *Session { return &Session{} })
+	if _, err := NewWarmPool[*Session](c, 1, nil); err == nil {
+		t.Error("pool of a singleton was created")
+	}
+}